@@ -0,0 +1,133 @@
+package timerange
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+var ErrNoData = errors.New("no data in range")
+
+// Number - числовые типы, по которым можно считать взвешенные по времени агрегаты.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// Stats - взвешенные по времени агрегаты ступенчатого ряда в пределах окна.
+type Stats[V Number] struct {
+	Average  float64 // среднее по покрытому времени
+	Min      V
+	Max      V
+	Integral float64       // сумма значение × секунды
+	Covered  time.Duration // время окна, на котором ряд определен
+}
+
+// --- Time-Weighted Aggregation ---
+
+// Aggregate считает агрегаты ряда series в окне q. Ряд должен состоять из
+// непересекающихся интервалов, как RangeMap.Intervals(); промежутки без
+// значений не учитываются. Если ряд не пересекает q, возвращается ErrNoData.
+func Aggregate[V Number](series []Interval[V], q TimeRange) (Stats[V], error) {
+	pieces := clipSeries(series, q)
+	if len(pieces) == 0 {
+		return Stats[V]{}, ErrNoData
+	}
+
+	stats := Stats[V]{Min: pieces[0].Value, Max: pieces[0].Value}
+	for _, p := range pieces {
+		stats.Min = min(stats.Min, p.Value)
+		stats.Max = max(stats.Max, p.Value)
+		stats.Integral += float64(p.Value) * p.Duration().Seconds()
+		stats.Covered += p.Duration()
+	}
+	stats.Average = stats.Integral / stats.Covered.Seconds()
+	return stats, nil
+}
+
+// TimeWeightedAverage - среднее значение ряда по покрытому времени окна q.
+func TimeWeightedAverage[V Number](series []Interval[V], q TimeRange) (float64, error) {
+	stats, err := Aggregate(series, q)
+	if err != nil {
+		return 0, err
+	}
+	return stats.Average, nil
+}
+
+// Integral возвращает сумму значение × длительность в единицах unit:
+// например, с unit = time.Hour загрузка в ядрах дает ядро-часы.
+func Integral[V Number](series []Interval[V], q TimeRange, unit time.Duration) (float64, error) {
+	if unit <= 0 {
+		return 0, ErrInvalidArgument
+	}
+	var total float64
+	for _, p := range clipSeries(series, q) {
+		total += float64(p.Value) * float64(p.Duration()) / float64(unit)
+	}
+	return total, nil
+}
+
+// Percentile возвращает взвешенный по времени перцентиль p (0-100): наименьшее
+// значение, которое ряд не превышает в течение p процентов покрытого времени.
+func Percentile[V Number](series []Interval[V], q TimeRange, p float64) (V, error) {
+	var zero V
+	if p < 0 || p > 100 {
+		return zero, ErrInvalidArgument
+	}
+	pieces := clipSeries(series, q)
+	if len(pieces) == 0 {
+		return zero, ErrNoData
+	}
+
+	sort.SliceStable(pieces, func(i, j int) bool {
+		return pieces[i].Value < pieces[j].Value
+	})
+
+	var covered time.Duration
+	for _, piece := range pieces {
+		covered += piece.Duration()
+	}
+
+	target := p / 100 * float64(covered)
+	var accumulated time.Duration
+	for _, piece := range pieces {
+		accumulated += piece.Duration()
+		if float64(accumulated) >= target {
+			return piece.Value, nil
+		}
+	}
+	return pieces[len(pieces)-1].Value, nil
+}
+
+// Resample делит окно q на корзины длительностью d (как SplitByDuration)
+// и считает агрегаты в каждой. Корзины без данных пропускаются.
+func Resample[V Number](series []Interval[V], q TimeRange, d time.Duration) ([]Interval[Stats[V]], error) {
+	if d <= 0 {
+		return nil, ErrInvalidArgument
+	}
+
+	var result []Interval[Stats[V]]
+	for bucket := range q.SplitByDurationSeq(d) {
+		stats, err := Aggregate(series, bucket)
+		if err == ErrNoData {
+			continue
+		}
+		result = append(result, Interval[Stats[V]]{TimeRange: bucket, Value: stats})
+	}
+	return result, nil
+}
+
+// --- Helper Functions ---
+
+// clipSeries возвращает непустые части ряда, обрезанные по окну q.
+func clipSeries[V any](series []Interval[V], q TimeRange) []Interval[V] {
+	var pieces []Interval[V]
+	for _, iv := range series {
+		start, end := maxTime(iv.Start, q.Start), minTime(iv.End, q.End)
+		if start.Before(end) {
+			pieces = append(pieces, Interval[V]{TimeRange: TimeRange{Start: start, End: end}, Value: iv.Value})
+		}
+	}
+	return pieces
+}
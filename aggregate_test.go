@@ -0,0 +1,164 @@
+package timerange
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// cpuSeries: 2 ядра [0,2), 4 ядра [2,3), промежуток [3,4), 1 ядро [4,8)
+func cpuSeries() []Interval[int] {
+	return []Interval[int]{
+		{TimeRange: hourRange(0, 2), Value: 2},
+		{TimeRange: hourRange(2, 3), Value: 4},
+		{TimeRange: hourRange(4, 8), Value: 1},
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    TimeRange
+		expected Stats[int]
+	}{
+		{
+			name:  "whole series",
+			query: hourRange(0, 8),
+			expected: Stats[int]{
+				Average:  12.0 / 7,
+				Min:      1,
+				Max:      4,
+				Integral: 12 * 3600,
+				Covered:  7 * time.Hour,
+			},
+		},
+		{
+			name:  "window clips pieces",
+			query: hourRange(1, 3),
+			expected: Stats[int]{
+				Average:  3,
+				Min:      2,
+				Max:      4,
+				Integral: 6 * 3600,
+				Covered:  2 * time.Hour,
+			},
+		},
+		{
+			name:  "gap is not counted",
+			query: hourRange(2, 5),
+			expected: Stats[int]{
+				Average:  2.5,
+				Min:      1,
+				Max:      4,
+				Integral: 5 * 3600,
+				Covered:  2 * time.Hour,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Aggregate(cpuSeries(), tt.query)
+			if err != nil {
+				t.Fatalf("Aggregate() error = %v", err)
+			}
+			if math.Abs(result.Average-tt.expected.Average) > 1e-9 ||
+				result.Min != tt.expected.Min || result.Max != tt.expected.Max ||
+				result.Integral != tt.expected.Integral || result.Covered != tt.expected.Covered {
+				t.Errorf("Aggregate() = %+v, want %+v", result, tt.expected)
+			}
+		})
+	}
+
+	t.Run("no data", func(t *testing.T) {
+		if _, err := Aggregate(cpuSeries(), hourRange(3, 4)); err != ErrNoData {
+			t.Errorf("Aggregate() error = %v, want ErrNoData", err)
+		}
+	})
+}
+
+func TestIntegral(t *testing.T) {
+	result, err := Integral(cpuSeries(), hourRange(0, 8), time.Hour)
+	if err != nil {
+		t.Fatalf("Integral() error = %v", err)
+	}
+	if result != 12 {
+		t.Errorf("Integral() = %v, want 12", result)
+	}
+
+	if _, err := Integral(cpuSeries(), hourRange(0, 8), 0); err != ErrInvalidArgument {
+		t.Errorf("Integral() error = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	// Покрыто 7 часов: 1 ядро - 4 часа, 2 ядра - 2 часа, 4 ядра - 1 час
+	tests := []struct {
+		p        float64
+		expected int
+	}{
+		{0, 1},
+		{50, 1},
+		{57, 1},
+		{60, 2},
+		{85, 2},
+		{90, 4},
+		{100, 4},
+	}
+
+	for _, tt := range tests {
+		result, err := Percentile(cpuSeries(), hourRange(0, 8), tt.p)
+		if err != nil {
+			t.Fatalf("Percentile(%v) error = %v", tt.p, err)
+		}
+		if result != tt.expected {
+			t.Errorf("Percentile(%v) = %v, want %v", tt.p, result, tt.expected)
+		}
+	}
+
+	if _, err := Percentile(cpuSeries(), hourRange(0, 8), 101); err != ErrInvalidArgument {
+		t.Errorf("Percentile() error = %v, want ErrInvalidArgument", err)
+	}
+	if _, err := Percentile(cpuSeries(), hourRange(3, 4), 50); err != ErrNoData {
+		t.Errorf("Percentile() error = %v, want ErrNoData", err)
+	}
+}
+
+func TestResample(t *testing.T) {
+	result, err := Resample(cpuSeries(), hourRange(0, 8), 2*time.Hour)
+	if err != nil {
+		t.Fatalf("Resample() error = %v", err)
+	}
+
+	expected := []struct {
+		bucket  TimeRange
+		average float64
+	}{
+		{hourRange(0, 2), 2},
+		{hourRange(2, 4), 4},
+		{hourRange(4, 6), 1},
+		{hourRange(6, 8), 1},
+	}
+	if len(result) != len(expected) {
+		t.Fatalf("Resample() returned %d buckets, want %d", len(result), len(expected))
+	}
+	for i, e := range expected {
+		if !result[i].Equal(e.bucket) || result[i].Value.Average != e.average {
+			t.Errorf("bucket %d = %v avg %v, want %v avg %v",
+				i, result[i].TimeRange, result[i].Value.Average, e.bucket, e.average)
+		}
+	}
+
+	t.Run("empty buckets are skipped", func(t *testing.T) {
+		result, _ := Resample(cpuSeries(), hourRange(2, 5), time.Hour)
+		if len(result) != 2 {
+			t.Errorf("Resample() returned %d buckets, want 2", len(result))
+		}
+	})
+
+	t.Run("invalid bucket size", func(t *testing.T) {
+		if _, err := Resample(cpuSeries(), hourRange(0, 8), 0); err != ErrInvalidArgument {
+			t.Errorf("Resample() error = %v, want ErrInvalidArgument", err)
+		}
+	})
+}
@@ -0,0 +1,322 @@
+package timerange
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrInconsistent = errors.New("relation network is inconsistent")
+
+// AllenRelation - одно из 13 базовых отношений интервальной алгебры Аллена.
+type AllenRelation uint8
+
+const (
+	RelBefore AllenRelation = iota
+	RelMeets
+	RelOverlaps
+	RelStarts
+	RelDuring
+	RelFinishes
+	RelEquals
+	RelAfter
+	RelMetBy
+	RelOverlappedBy
+	RelStartedBy
+	RelContains
+	RelFinishedBy
+
+	relationCount = 13
+)
+
+var relationNames = [relationCount]string{
+	"before", "meets", "overlaps", "starts", "during", "finishes", "equals",
+	"after", "met-by", "overlapped-by", "started-by", "contains", "finished-by",
+}
+
+func (r AllenRelation) String() string {
+	if r >= relationCount {
+		return "unknown"
+	}
+	return relationNames[r]
+}
+
+func (r AllenRelation) Inverse() AllenRelation {
+	switch {
+	case r == RelEquals:
+		return RelEquals
+	case r < RelEquals:
+		return r + RelAfter
+	default:
+		return r - RelAfter
+	}
+}
+
+// --- Relation ---
+
+func Relation(a, b TimeRange) AllenRelation {
+	ss := a.Start.Compare(b.Start)
+	ee := a.End.Compare(b.End)
+
+	switch {
+	case ss == 0 && ee == 0:
+		return RelEquals
+	case a.End.Before(b.Start):
+		return RelBefore
+	case a.End.Equal(b.Start):
+		return RelMeets
+	case a.Start.After(b.End):
+		return RelAfter
+	case a.Start.Equal(b.End):
+		return RelMetBy
+	case ss == 0 && ee < 0:
+		return RelStarts
+	case ss == 0:
+		return RelStartedBy
+	case ee == 0 && ss > 0:
+		return RelFinishes
+	case ee == 0:
+		return RelFinishedBy
+	case ss > 0 && ee < 0:
+		return RelDuring
+	case ss < 0 && ee > 0:
+		return RelContains
+	case ss < 0:
+		return RelOverlaps
+	default:
+		return RelOverlappedBy
+	}
+}
+
+func (tr TimeRange) Relation(other TimeRange) AllenRelation {
+	return Relation(tr, other)
+}
+
+func (tr TimeRange) Is(rel AllenRelation, other TimeRange) bool {
+	return Relation(tr, other) == rel
+}
+
+func (tr TimeRange) IsBefore(other TimeRange) bool {
+	return tr.Is(RelBefore, other)
+}
+
+func (tr TimeRange) IsAfter(other TimeRange) bool {
+	return tr.Is(RelAfter, other)
+}
+
+func (tr TimeRange) Meets(other TimeRange) bool {
+	return tr.Is(RelMeets, other)
+}
+
+func (tr TimeRange) Starts(other TimeRange) bool {
+	return tr.Is(RelStarts, other)
+}
+
+func (tr TimeRange) During(other TimeRange) bool {
+	return tr.Is(RelDuring, other)
+}
+
+func (tr TimeRange) Finishes(other TimeRange) bool {
+	return tr.Is(RelFinishes, other)
+}
+
+// --- Relation Sets ---
+
+// RelationSet - дизъюнкция отношений Аллена (битовая маска).
+type RelationSet uint16
+
+const AllRelations RelationSet = 1<<relationCount - 1
+
+func NewRelationSet(rels ...AllenRelation) RelationSet {
+	var s RelationSet
+	for _, r := range rels {
+		s |= 1 << r
+	}
+	return s
+}
+
+func (s RelationSet) Has(r AllenRelation) bool {
+	return s&(1<<r) != 0
+}
+
+func (s RelationSet) IsEmpty() bool {
+	return s == 0
+}
+
+func (s RelationSet) Relations() []AllenRelation {
+	var rels []AllenRelation
+	for r := AllenRelation(0); r < relationCount; r++ {
+		if s.Has(r) {
+			rels = append(rels, r)
+		}
+	}
+	return rels
+}
+
+func (s RelationSet) Inverse() RelationSet {
+	var inv RelationSet
+	for _, r := range s.Relations() {
+		inv |= 1 << r.Inverse()
+	}
+	return inv
+}
+
+func (s RelationSet) String() string {
+	names := make([]string, 0, relationCount)
+	for _, r := range s.Relations() {
+		names = append(names, r.String())
+	}
+	return "{" + strings.Join(names, ", ") + "}"
+}
+
+// Compose возвращает возможные отношения a-c, если a s b и b other c.
+func (s RelationSet) Compose(other RelationSet) RelationSet {
+	table := compositionTable()
+	var result RelationSet
+	for _, r1 := range s.Relations() {
+		for _, r2 := range other.Relations() {
+			result |= table[r1][r2]
+		}
+	}
+	return result
+}
+
+var (
+	compositionOnce sync.Once
+	composition     [relationCount][relationCount]RelationSet
+)
+
+// compositionTable строит таблицу композиции перебором: трех интервалов
+// достаточно шести различных точек, поэтому концы берутся из 0..5.
+func compositionTable() *[relationCount][relationCount]RelationSet {
+	compositionOnce.Do(func() {
+		var ranges []TimeRange
+		for s := 0; s < 6; s++ {
+			for e := s + 1; e < 6; e++ {
+				ranges = append(ranges, TimeRange{
+					Start: time.Unix(int64(s), 0),
+					End:   time.Unix(int64(e), 0),
+				})
+			}
+		}
+		for _, a := range ranges {
+			for _, b := range ranges {
+				ab := Relation(a, b)
+				for _, c := range ranges {
+					composition[ab][Relation(b, c)] |= 1 << Relation(a, c)
+				}
+			}
+		}
+	})
+	return &composition
+}
+
+// --- Constraint Network ---
+
+// Network - сеть ограничений между n интервалами, заданных множествами
+// отношений Аллена. Propagate сужает ограничения алгоритмом path consistency.
+type Network struct {
+	constraints [][]RelationSet
+}
+
+func NewNetwork(n int) *Network {
+	constraints := make([][]RelationSet, n)
+	for i := range constraints {
+		constraints[i] = make([]RelationSet, n)
+		for j := range constraints[i] {
+			constraints[i][j] = AllRelations
+		}
+		constraints[i][i] = NewRelationSet(RelEquals)
+	}
+	return &Network{constraints: constraints}
+}
+
+func (n *Network) Size() int {
+	return len(n.constraints)
+}
+
+// Constrain пересекает текущее ограничение i-j с rels.
+func (n *Network) Constrain(i, j int, rels RelationSet) error {
+	if i < 0 || j < 0 || i >= n.Size() || j >= n.Size() {
+		return ErrInvalidArgument
+	}
+	n.constraints[i][j] &= rels
+	n.constraints[j][i] &= rels.Inverse()
+	if n.constraints[i][j].IsEmpty() {
+		return ErrInconsistent
+	}
+	return nil
+}
+
+func (n *Network) Relations(i, j int) RelationSet {
+	return n.constraints[i][j]
+}
+
+func (n *Network) Propagate() error {
+	type edge struct{ i, j int }
+
+	size := n.Size()
+	queue := make([]edge, 0, size*size)
+	queued := make(map[edge]bool, size*size)
+	for i := 0; i < size; i++ {
+		for j := 0; j < size; j++ {
+			if i != j {
+				queue = append(queue, edge{i, j})
+				queued[edge{i, j}] = true
+			}
+		}
+	}
+
+	revise := func(i, k, j int) (bool, error) {
+		narrowed := n.constraints[i][j] & n.constraints[i][k].Compose(n.constraints[k][j])
+		if narrowed == n.constraints[i][j] {
+			return false, nil
+		}
+		if narrowed.IsEmpty() {
+			return false, ErrInconsistent
+		}
+		n.constraints[i][j] = narrowed
+		n.constraints[j][i] = narrowed.Inverse()
+		return true, nil
+	}
+
+	for len(queue) > 0 {
+		e := queue[0]
+		queue = queue[1:]
+		delete(queued, e)
+
+		for k := 0; k < size; k++ {
+			if k == e.i || k == e.j {
+				continue
+			}
+			for _, path := range [][3]int{{e.i, e.j, k}, {k, e.i, e.j}} {
+				changed, err := revise(path[0], path[1], path[2])
+				if err != nil {
+					return err
+				}
+				target := edge{path[0], path[2]}
+				if changed && !queued[target] {
+					queue = append(queue, target)
+					queued[target] = true
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Satisfied проверяет, что конкретные интервалы удовлетворяют всем ограничениям.
+func (n *Network) Satisfied(ranges []TimeRange) bool {
+	if len(ranges) != n.Size() {
+		return false
+	}
+	for i := range ranges {
+		for j := range ranges {
+			if !n.constraints[i][j].Has(Relation(ranges[i], ranges[j])) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,111 @@
+package timerange
+
+import "testing"
+
+func TestRelation(t *testing.T) {
+	base := hourRange(2, 6)
+
+	tests := []struct {
+		name   string
+		other  TimeRange
+		expect AllenRelation
+	}{
+		{name: "before", other: hourRange(0, 1), expect: RelBefore},
+		{name: "meets", other: hourRange(0, 2), expect: RelMeets},
+		{name: "overlaps", other: hourRange(0, 3), expect: RelOverlaps},
+		{name: "started by", other: hourRange(2, 8), expect: RelStartedBy},
+		{name: "contains", other: hourRange(0, 8), expect: RelContains},
+		{name: "finished by", other: hourRange(0, 6), expect: RelFinishedBy},
+		{name: "equals", other: hourRange(2, 6), expect: RelEquals},
+		{name: "starts", other: hourRange(2, 4), expect: RelStarts},
+		{name: "during", other: hourRange(3, 5), expect: RelDuring},
+		{name: "finishes", other: hourRange(4, 6), expect: RelFinishes},
+		{name: "overlapped by", other: hourRange(5, 8), expect: RelOverlappedBy},
+		{name: "met by", other: hourRange(6, 8), expect: RelMetBy},
+		{name: "after", other: hourRange(7, 8), expect: RelAfter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Relation(tt.other, base); got != tt.expect {
+				t.Errorf("Relation() = %v, want %v", got, tt.expect)
+			}
+			if got := Relation(base, tt.other); got != tt.expect.Inverse() {
+				t.Errorf("inverse Relation() = %v, want %v", got, tt.expect.Inverse())
+			}
+		})
+	}
+}
+
+func TestRelationPredicates(t *testing.T) {
+	if !hourRange(0, 2).Meets(hourRange(2, 4)) {
+		t.Error("Meets() = false, want true")
+	}
+	if !hourRange(3, 4).During(hourRange(2, 6)) {
+		t.Error("During() = false, want true")
+	}
+	if hourRange(0, 2).IsBefore(hourRange(2, 4)) {
+		t.Error("IsBefore() = true for meeting ranges, want false")
+	}
+}
+
+func TestCompose(t *testing.T) {
+	before := NewRelationSet(RelBefore)
+	if got := before.Compose(before); got != before {
+		t.Errorf("before∘before = %v, want %v", got, before)
+	}
+
+	during := NewRelationSet(RelDuring)
+	if got := during.Compose(during); got != during {
+		t.Errorf("during∘during = %v, want %v", got, during)
+	}
+
+	meets := NewRelationSet(RelMeets)
+	expected := NewRelationSet(RelOverlaps, RelStarts, RelDuring)
+	if got := meets.Compose(during); got != expected {
+		t.Errorf("meets∘during = %v, want %v", got, expected)
+	}
+}
+
+func TestNetwork(t *testing.T) {
+	t.Run("propagation narrows constraints", func(t *testing.T) {
+		n := NewNetwork(3)
+		if err := n.Constrain(0, 1, NewRelationSet(RelBefore, RelMeets)); err != nil {
+			t.Fatal(err)
+		}
+		if err := n.Constrain(1, 2, NewRelationSet(RelBefore)); err != nil {
+			t.Fatal(err)
+		}
+		if err := n.Propagate(); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := NewRelationSet(RelBefore)
+		if got := n.Relations(0, 2); got != expected {
+			t.Errorf("Relations(0, 2) = %v, want %v", got, expected)
+		}
+		if got := n.Relations(2, 0); got != expected.Inverse() {
+			t.Errorf("Relations(2, 0) = %v, want %v", got, expected.Inverse())
+		}
+		if !n.Satisfied([]TimeRange{hourRange(0, 1), hourRange(1, 2), hourRange(3, 4)}) {
+			t.Error("Satisfied() = false, want true")
+		}
+	})
+
+	t.Run("inconsistent network", func(t *testing.T) {
+		n := NewNetwork(3)
+		_ = n.Constrain(0, 1, NewRelationSet(RelBefore))
+		_ = n.Constrain(1, 2, NewRelationSet(RelBefore))
+		_ = n.Constrain(2, 0, NewRelationSet(RelBefore))
+		if err := n.Propagate(); err != ErrInconsistent {
+			t.Errorf("Propagate() error = %v, want ErrInconsistent", err)
+		}
+	})
+
+	t.Run("invalid index", func(t *testing.T) {
+		n := NewNetwork(2)
+		if err := n.Constrain(0, 2, AllRelations); err != ErrInvalidArgument {
+			t.Errorf("Constrain() error = %v, want ErrInvalidArgument", err)
+		}
+	})
+}
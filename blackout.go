@@ -0,0 +1,173 @@
+package timerange
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrBlackout        = errors.New("time is inside a blackout window")
+	ErrNoAllowedWindow = errors.New("no allowed window within horizon")
+)
+
+// DefaultBlackoutHorizon - насколько далеко NextAllowed и NextWindow ищут разрешенное время.
+const DefaultBlackoutHorizon = 366 * 24 * time.Hour
+
+// BlackoutError описывает правило, запрещающее запланированный интервал,
+// и сопоставляется с ErrBlackout.
+type BlackoutError struct {
+	Rule   string
+	Window TimeRange // пересечение плана с запретом
+}
+
+func (e *BlackoutError) Error() string {
+	return fmt.Sprintf("blocked by %q during %s", e.Rule, e.Window.ToISOString())
+}
+
+func (e *BlackoutError) Is(target error) bool {
+	return target == ErrBlackout
+}
+
+// BlackoutRule - именованный источник запрещенных интервалов: фиксированные
+// интервалы, CronWindow, WeeklyWindow, Expr или любой другой Source.
+type BlackoutRule struct {
+	Name   string
+	Source Source
+}
+
+// BlackoutPolicy проверяет, разрешено ли время, например, для выкладок.
+// Правила проверяются в порядке добавления.
+type BlackoutPolicy struct {
+	Rules   []BlackoutRule
+	Horizon time.Duration // по умолчанию DefaultBlackoutHorizon
+}
+
+func NewBlackoutPolicy(rules ...BlackoutRule) *BlackoutPolicy {
+	return &BlackoutPolicy{Rules: rules}
+}
+
+func (p *BlackoutPolicy) Add(name string, source Source) *BlackoutPolicy {
+	p.Rules = append(p.Rules, BlackoutRule{Name: name, Source: source})
+	return p
+}
+
+// Allowed сообщает, что t не попадает ни в одно запрещенное окно.
+func (p *BlackoutPolicy) Allowed(t time.Time) bool {
+	_, blocked := p.Reason(t)
+	return !blocked
+}
+
+// Reason возвращает имя первого правила, запрещающего момент t.
+func (p *BlackoutPolicy) Reason(t time.Time) (string, bool) {
+	instant := TimeRange{Start: t, End: t.Add(time.Nanosecond)}
+	for _, rule := range p.Rules {
+		for _, tr := range rule.Source.Ranges(instant) {
+			if !t.Before(tr.Start) && t.Before(tr.End) {
+				return rule.Name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Validate проверяет, что план целиком лежит в разрешенном времени.
+// При нарушении возвращается *BlackoutError для первого сработавшего правила.
+func (p *BlackoutPolicy) Validate(plan TimeRange) error {
+	for _, rule := range p.Rules {
+		for _, tr := range rule.Source.Ranges(plan) {
+			start, end := maxTime(tr.Start, plan.Start), minTime(tr.End, plan.End)
+			if start.Before(end) {
+				return &BlackoutError{Rule: rule.Name, Window: TimeRange{Start: start, End: end}}
+			}
+		}
+	}
+	return nil
+}
+
+// NextAllowed возвращает первый разрешенный момент, не раньше t.
+func (p *BlackoutPolicy) NextAllowed(t time.Time) (time.Time, error) {
+	window, err := p.NextWindow(t, 0)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return window.Start, nil
+}
+
+// NextWindow возвращает самый ранний интервал длительностью d, начинающийся
+// не раньше t и целиком лежащий в разрешенном времени.
+func (p *BlackoutPolicy) NextWindow(t time.Time, d time.Duration) (TimeRange, error) {
+	if d < 0 {
+		return TimeRange{}, ErrInvalidArgument
+	}
+	search := TimeRange{Start: t, End: t.Add(p.horizon())}
+
+	for _, gap := range search.SubtractAll(p.Blackouts(search)) {
+		if gap.Duration() > 0 && gap.Duration() >= d {
+			return TimeRange{Start: gap.Start, End: gap.Start.Add(d)}, nil
+		}
+	}
+	return TimeRange{}, ErrNoAllowedWindow
+}
+
+// Blackouts возвращает объединенные запрещенные интервалы всех правил в пределах bounds.
+func (p *BlackoutPolicy) Blackouts(bounds TimeRange) []TimeRange {
+	var all []TimeRange
+	for _, rule := range p.Rules {
+		all = append(all, rule.Source.Ranges(bounds)...)
+	}
+	return clipSet(all, bounds)
+}
+
+func (p *BlackoutPolicy) horizon() time.Duration {
+	if p.Horizon <= 0 {
+		return DefaultBlackoutHorizon
+	}
+	return p.Horizon
+}
+
+// --- Sources ---
+
+// FixedRanges - источник из заранее известных интервалов.
+func FixedRanges(ranges ...TimeRange) Source {
+	return SourceFunc(func(bounds TimeRange) []TimeRange {
+		var result []TimeRange
+		for _, tr := range ranges {
+			if tr.Overlaps(bounds) {
+				result = append(result, tr)
+			}
+		}
+		return result
+	})
+}
+
+// WeeklyWindow - еженедельное окно от дня from и времени суток fromClock до
+// дня to и времени toClock в зоне loc, например с пятницы 18:00 до понедельника 06:00.
+func WeeklyWindow(from time.Weekday, fromClock time.Duration, to time.Weekday, toClock time.Duration, loc *time.Location) Source {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return SourceFunc(func(bounds TimeRange) []TimeRange {
+		var result []TimeRange
+		week := addUnits(startOfUnit(bounds.Start, UnitWeek, loc, time.Monday), UnitWeek, -1)
+		for ; week.Before(bounds.End); week = addUnits(week, UnitWeek, 1) {
+			start := weekdayClock(week, from, fromClock)
+			end := weekdayClock(week, to, toClock)
+			if !end.After(start) {
+				end = weekdayClock(addUnits(week, UnitWeek, 1), to, toClock)
+			}
+			if tr := (TimeRange{Start: start, End: end}); tr.Overlaps(bounds) {
+				result = append(result, tr)
+			}
+		}
+		return result
+	})
+}
+
+// weekdayClock возвращает время суток clock в день day недели, начинающейся с понедельника week.
+func weekdayClock(week time.Time, day time.Weekday, clock time.Duration) time.Time {
+	y, m, d := week.Date()
+	offset := (int(day) + 6) % 7
+	h, min, sec := int(clock/time.Hour), int(clock%time.Hour/time.Minute), int(clock%time.Minute/time.Second)
+	return time.Date(y, m, d+offset, h, min, sec, int(clock%time.Second), week.Location())
+}
@@ -0,0 +1,166 @@
+package timerange
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestBlackoutPolicy(t *testing.T) *BlackoutPolicy {
+	t.Helper()
+	maintenance, err := NewCronWindow("0 2 * * WED", 3*time.Hour, time.UTC)
+	if err != nil {
+		t.Fatalf("NewCronWindow() error = %v", err)
+	}
+	return NewBlackoutPolicy().
+		Add("release freeze", FixedRanges(TimeRange{Start: mayAt(7, 12), End: mayAt(7, 18)})).
+		Add("maintenance", maintenance).
+		Add("weekend", WeeklyWindow(time.Friday, 18*time.Hour, time.Monday, 6*time.Hour, time.UTC))
+}
+
+func TestBlackoutPolicyReason(t *testing.T) {
+	policy := newTestBlackoutPolicy(t)
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		expected string
+	}{
+		{"monday morning is open", mayAt(6, 10), ""},
+		{"early monday is weekend", mayAt(6, 5), "weekend"},
+		{"monday 06:00 is open", mayAt(6, 6), ""},
+		{"fixed freeze", mayAt(7, 12), "release freeze"},
+		{"freeze end is open", mayAt(7, 18), ""},
+		{"cron maintenance", mayAt(8, 3), "maintenance"},
+		{"friday before weekend", mayAt(10, 17), ""},
+		{"friday evening", mayAt(10, 18), "weekend"},
+		{"sunday", mayAt(12, 12), "weekend"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, blocked := policy.Reason(tt.at)
+			if reason != tt.expected || blocked != (tt.expected != "") {
+				t.Errorf("Reason() = %q, %v, want %q", reason, blocked, tt.expected)
+			}
+			if allowed := policy.Allowed(tt.at); allowed != (tt.expected == "") {
+				t.Errorf("Allowed() = %v, want %v", allowed, tt.expected == "")
+			}
+		})
+	}
+}
+
+func TestBlackoutPolicyNextAllowed(t *testing.T) {
+	policy := newTestBlackoutPolicy(t)
+
+	tests := []struct {
+		name     string
+		from     time.Time
+		expected time.Time
+	}{
+		{"allowed time is returned as is", mayAt(6, 10), mayAt(6, 10)},
+		{"inside freeze", mayAt(7, 13), mayAt(7, 18)},
+		{"weekend ends monday", mayAt(11, 9), mayAt(13, 6)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := policy.NextAllowed(tt.from)
+			if err != nil {
+				t.Fatalf("NextAllowed() error = %v", err)
+			}
+			if !got.Equal(tt.expected) {
+				t.Errorf("NextAllowed() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	t.Run("horizon exceeded", func(t *testing.T) {
+		always := NewBlackoutPolicy(BlackoutRule{Name: "always", Source: SourceFunc(func(bounds TimeRange) []TimeRange {
+			return []TimeRange{bounds}
+		})})
+		always.Horizon = 24 * time.Hour
+		if _, err := always.NextAllowed(mayAt(6, 0)); err != ErrNoAllowedWindow {
+			t.Errorf("NextAllowed() error = %v, want ErrNoAllowedWindow", err)
+		}
+	})
+}
+
+func TestBlackoutPolicyValidate(t *testing.T) {
+	policy := newTestBlackoutPolicy(t)
+
+	if err := policy.Validate(TimeRange{Start: mayAt(7, 9), End: mayAt(7, 12)}); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+
+	err := policy.Validate(TimeRange{Start: mayAt(7, 10), End: mayAt(7, 14)})
+	if !errors.Is(err, ErrBlackout) {
+		t.Fatalf("Validate() error = %v, want ErrBlackout", err)
+	}
+	var blackoutErr *BlackoutError
+	if !errors.As(err, &blackoutErr) {
+		t.Fatalf("Validate() error = %T, want *BlackoutError", err)
+	}
+	expected := TimeRange{Start: mayAt(7, 12), End: mayAt(7, 14)}
+	if blackoutErr.Rule != "release freeze" || !blackoutErr.Window.Equal(expected) {
+		t.Errorf("Validate() = %q %v, want %q %v", blackoutErr.Rule, blackoutErr.Window, "release freeze", expected)
+	}
+}
+
+func TestBlackoutPolicyNextWindow(t *testing.T) {
+	policy := newTestBlackoutPolicy(t)
+
+	tests := []struct {
+		name     string
+		from     time.Time
+		duration time.Duration
+		expected time.Time
+	}{
+		{"fits before freeze", mayAt(7, 9), 3 * time.Hour, mayAt(7, 9)},
+		{"skips too short gap", mayAt(7, 9), 4 * time.Hour, mayAt(7, 18)},
+		{"skips maintenance", mayAt(7, 23), 4 * time.Hour, mayAt(8, 5)},
+		{"exact fit", mayAt(10, 14), 4 * time.Hour, mayAt(10, 14)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, err := policy.NextWindow(tt.from, tt.duration)
+			if err != nil {
+				t.Fatalf("NextWindow() error = %v", err)
+			}
+			if !window.Start.Equal(tt.expected) || window.Duration() != tt.duration {
+				t.Errorf("NextWindow() = %v, want start %v", window, tt.expected)
+			}
+			if err := policy.Validate(window); err != nil {
+				t.Errorf("Validate(NextWindow()) error = %v", err)
+			}
+		})
+	}
+
+	t.Run("negative duration", func(t *testing.T) {
+		if _, err := policy.NextWindow(mayAt(7, 9), -time.Hour); err != ErrInvalidArgument {
+			t.Errorf("NextWindow() error = %v, want ErrInvalidArgument", err)
+		}
+	})
+}
+
+func TestWeeklyWindowDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+	// Переход на летнее время в ночь на воскресенье 31 марта 2024
+	source := WeeklyWindow(time.Saturday, 22*time.Hour, time.Sunday, 6*time.Hour, berlin)
+	bounds := TimeRange{
+		Start: time.Date(2024, 3, 30, 0, 0, 0, 0, berlin),
+		End:   time.Date(2024, 4, 1, 0, 0, 0, 0, berlin),
+	}
+
+	ranges := source.Ranges(bounds)
+	if len(ranges) != 1 {
+		t.Fatalf("Ranges() = %v, want one window", ranges)
+	}
+	if ranges[0].Start.Hour() != 22 || ranges[0].End.In(berlin).Hour() != 6 || ranges[0].Duration() != 7*time.Hour {
+		t.Errorf("Ranges() = %v, want 22:00-06:00 local lasting 7h", ranges[0])
+	}
+}
@@ -0,0 +1,445 @@
+// Package booking хранит бронирования ресурсов по времени и атомарно отклоняет
+// пересекающиеся резервы. ReservationStore безопасен для конкурентного использования.
+package booking
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/GiBi-develop/timerange/v1"
+)
+
+var (
+	ErrConflict    = errors.New("reservation conflicts with an existing one")
+	ErrNotFound    = errors.New("reservation not found")
+	ErrHoldExpired = errors.New("hold has expired")
+)
+
+// ConflictError описывает конкретное пересечение и сопоставляется с ErrConflict.
+type ConflictError struct {
+	Requested Reservation
+	Existing  Reservation
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("resource %q: %s overlaps reservation %s (%s)",
+		e.Requested.Resource, e.Requested.Range.ToISOString(), e.Existing.ID, e.Existing.Range.ToISOString())
+}
+
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+type Status int
+
+const (
+	StatusConfirmed Status = iota + 1
+	StatusHeld
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusConfirmed:
+		return "confirmed"
+	case StatusHeld:
+		return "held"
+	default:
+		return "unknown"
+	}
+}
+
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Status) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "confirmed":
+		*s = StatusConfirmed
+	case "held":
+		*s = StatusHeld
+	default:
+		return fmt.Errorf("unknown reservation status %q", text)
+	}
+	return nil
+}
+
+type Reservation struct {
+	ID        string              `json:"id"`
+	Resource  string              `json:"resource"`
+	Range     timerange.TimeRange `json:"range"`
+	Status    Status              `json:"status"`
+	ExpiresAt time.Time           `json:"expires_at"` // только для StatusHeld
+}
+
+// Active сообщает, занимает ли бронь время: подтвержденные всегда, холды - до истечения.
+func (r Reservation) Active(now time.Time) bool {
+	return r.Status != StatusHeld || now.Before(r.ExpiresAt)
+}
+
+type Options struct {
+	Clock       timerange.Clock // по умолчанию timerange.SystemClock
+	IDGenerator func() string   // по умолчанию случайный hex
+}
+
+type ReservationStore struct {
+	mu         sync.Mutex
+	store      Store
+	clock      timerange.Clock
+	newID      func() string
+	byID       map[string]Reservation
+	byResource map[string]map[string]struct{}
+}
+
+func NewReservationStore(store Store, opts Options) (*ReservationStore, error) {
+	if opts.Clock == nil {
+		opts.Clock = timerange.SystemClock
+	}
+	if opts.IDGenerator == nil {
+		opts.IDGenerator = randomID
+	}
+
+	loaded, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &ReservationStore{
+		store:      store,
+		clock:      opts.Clock,
+		newID:      opts.IDGenerator,
+		byID:       make(map[string]Reservation, len(loaded)),
+		byResource: make(map[string]map[string]struct{}),
+	}
+	for _, r := range loaded {
+		s.index(r)
+	}
+	return s, nil
+}
+
+// --- Single Operations ---
+
+func (s *ReservationStore) Reserve(resource string, tr timerange.TimeRange) (Reservation, error) {
+	var result Reservation
+	err := s.Transaction(func(tx *Tx) (err error) {
+		result, err = tx.Reserve(resource, tr)
+		return err
+	})
+	return result, err
+}
+
+// Hold создает предварительную бронь, которая истекает через ttl, если не подтверждена.
+func (s *ReservationStore) Hold(resource string, tr timerange.TimeRange, ttl time.Duration) (Reservation, error) {
+	var result Reservation
+	err := s.Transaction(func(tx *Tx) (err error) {
+		result, err = tx.Hold(resource, tr, ttl)
+		return err
+	})
+	return result, err
+}
+
+func (s *ReservationStore) Confirm(id string) (Reservation, error) {
+	var result Reservation
+	err := s.Transaction(func(tx *Tx) (err error) {
+		result, err = tx.Confirm(id)
+		return err
+	})
+	return result, err
+}
+
+func (s *ReservationStore) Modify(id string, tr timerange.TimeRange) (Reservation, error) {
+	var result Reservation
+	err := s.Transaction(func(tx *Tx) (err error) {
+		result, err = tx.Modify(id, tr)
+		return err
+	})
+	return result, err
+}
+
+func (s *ReservationStore) Cancel(id string) error {
+	return s.Transaction(func(tx *Tx) error {
+		return tx.Cancel(id)
+	})
+}
+
+func (s *ReservationStore) Get(id string) (Reservation, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.byID[id]
+	return r, ok
+}
+
+// List возвращает активные брони ресурса, упорядоченные по началу.
+func (s *ReservationStore) List(resource string) []Reservation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	var result []Reservation
+	for id := range s.byResource[resource] {
+		if r := s.byID[id]; r.Active(now) {
+			result = append(result, r)
+		}
+	}
+	sortReservations(result)
+	return result
+}
+
+// Expire удаляет истекшие холды и возвращает их число.
+func (s *ReservationStore) Expire() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	var changes []Change
+	for _, r := range s.byID {
+		if !r.Active(now) {
+			changes = append(changes, Change{Op: OpDelete, Reservation: r})
+		}
+	}
+	if len(changes) == 0 {
+		return 0, nil
+	}
+	if err := s.store.Apply(changes); err != nil {
+		return 0, err
+	}
+	s.apply(changes)
+	return len(changes), nil
+}
+
+// --- Transactions ---
+
+// Transaction выполняет fn над изолированной копией состояния. Если fn вернула
+// ошибку или хранилище не приняло изменения, ничего не применяется.
+func (s *ReservationStore) Transaction(fn func(tx *Tx) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx := &Tx{s: s, now: s.clock.Now(), staged: make(map[string]*Reservation)}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	changes := tx.changes()
+	if len(changes) == 0 {
+		return nil
+	}
+	if err := s.store.Apply(changes); err != nil {
+		return err
+	}
+	s.apply(changes)
+	return nil
+}
+
+// Tx - пакет операций над несколькими ресурсами, применяемый атомарно.
+type Tx struct {
+	s      *ReservationStore
+	now    time.Time
+	staged map[string]*Reservation // nil - бронь удалена в транзакции
+	order  []string
+}
+
+func (tx *Tx) Reserve(resource string, tr timerange.TimeRange) (Reservation, error) {
+	return tx.create(resource, tr, StatusConfirmed, time.Time{})
+}
+
+func (tx *Tx) Hold(resource string, tr timerange.TimeRange, ttl time.Duration) (Reservation, error) {
+	if ttl <= 0 {
+		return Reservation{}, timerange.ErrInvalidArgument
+	}
+	return tx.create(resource, tr, StatusHeld, tx.now.Add(ttl))
+}
+
+func (tx *Tx) Confirm(id string) (Reservation, error) {
+	r, err := tx.active(id)
+	if err != nil {
+		return Reservation{}, err
+	}
+	r.Status = StatusConfirmed
+	r.ExpiresAt = time.Time{}
+	tx.put(r)
+	return r, nil
+}
+
+func (tx *Tx) Modify(id string, tr timerange.TimeRange) (Reservation, error) {
+	r, err := tx.active(id)
+	if err != nil {
+		return Reservation{}, err
+	}
+	if err := validateRange(tr); err != nil {
+		return Reservation{}, err
+	}
+	r.Range = tr
+	if err := tx.checkConflict(r); err != nil {
+		return Reservation{}, err
+	}
+	tx.put(r)
+	return r, nil
+}
+
+func (tx *Tx) Cancel(id string) error {
+	r, ok := tx.Get(id)
+	if !ok {
+		return ErrNotFound
+	}
+	tx.stage(r.ID, nil)
+	return nil
+}
+
+func (tx *Tx) Get(id string) (Reservation, bool) {
+	if staged, ok := tx.staged[id]; ok {
+		if staged == nil {
+			return Reservation{}, false
+		}
+		return *staged, true
+	}
+	r, ok := tx.s.byID[id]
+	return r, ok
+}
+
+func (tx *Tx) create(resource string, tr timerange.TimeRange, status Status, expiresAt time.Time) (Reservation, error) {
+	if resource == "" {
+		return Reservation{}, timerange.ErrInvalidArgument
+	}
+	if err := validateRange(tr); err != nil {
+		return Reservation{}, err
+	}
+
+	r := Reservation{
+		ID:        tx.s.newID(),
+		Resource:  resource,
+		Range:     tr,
+		Status:    status,
+		ExpiresAt: expiresAt,
+	}
+	if err := tx.checkConflict(r); err != nil {
+		return Reservation{}, err
+	}
+	tx.put(r)
+	return r, nil
+}
+
+func (tx *Tx) active(id string) (Reservation, error) {
+	r, ok := tx.Get(id)
+	if !ok {
+		return Reservation{}, ErrNotFound
+	}
+	if !r.Active(tx.now) {
+		return Reservation{}, ErrHoldExpired
+	}
+	return r, nil
+}
+
+func (tx *Tx) checkConflict(r Reservation) error {
+	check := func(other Reservation) error {
+		if other.ID != r.ID && other.Active(tx.now) && other.Range.Overlaps(r.Range) {
+			return &ConflictError{Requested: r, Existing: other}
+		}
+		return nil
+	}
+
+	for id := range tx.s.byResource[r.Resource] {
+		if _, ok := tx.staged[id]; ok {
+			continue
+		}
+		if err := check(tx.s.byID[id]); err != nil {
+			return err
+		}
+	}
+	for _, id := range tx.order {
+		if staged := tx.staged[id]; staged != nil && staged.Resource == r.Resource {
+			if err := check(*staged); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (tx *Tx) put(r Reservation) {
+	tx.stage(r.ID, &r)
+}
+
+func (tx *Tx) stage(id string, r *Reservation) {
+	if _, ok := tx.staged[id]; !ok {
+		tx.order = append(tx.order, id)
+	}
+	tx.staged[id] = r
+}
+
+func (tx *Tx) changes() []Change {
+	changes := make([]Change, 0, len(tx.order))
+	for _, id := range tx.order {
+		if r := tx.staged[id]; r != nil {
+			changes = append(changes, Change{Op: OpPut, Reservation: *r})
+		} else if _, existed := tx.s.byID[id]; existed {
+			changes = append(changes, Change{Op: OpDelete, Reservation: Reservation{ID: id}})
+		}
+	}
+	return changes
+}
+
+// --- Helper Functions ---
+
+func (s *ReservationStore) apply(changes []Change) {
+	for _, c := range changes {
+		if old, ok := s.byID[c.Reservation.ID]; ok {
+			s.unindex(old)
+		}
+		if c.Op == OpPut {
+			s.index(c.Reservation)
+		}
+	}
+}
+
+func (s *ReservationStore) index(r Reservation) {
+	s.byID[r.ID] = r
+	ids, ok := s.byResource[r.Resource]
+	if !ok {
+		ids = make(map[string]struct{})
+		s.byResource[r.Resource] = ids
+	}
+	ids[r.ID] = struct{}{}
+}
+
+func (s *ReservationStore) unindex(r Reservation) {
+	delete(s.byID, r.ID)
+	if ids := s.byResource[r.Resource]; ids != nil {
+		delete(ids, r.ID)
+		if len(ids) == 0 {
+			delete(s.byResource, r.Resource)
+		}
+	}
+}
+
+func validateRange(tr timerange.TimeRange) error {
+	if _, err := timerange.New(tr.Start, tr.End); err != nil {
+		return err
+	}
+	if !tr.Start.Before(tr.End) {
+		return timerange.ErrInvalidRange
+	}
+	return nil
+}
+
+func sortReservations(rs []Reservation) {
+	sort.Slice(rs, func(i, j int) bool {
+		if rs[i].Range.Start.Equal(rs[j].Range.Start) {
+			return rs[i].ID < rs[j].ID
+		}
+		return rs[i].Range.Start.Before(rs[j].Range.Start)
+	})
+}
+
+func randomID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,242 @@
+package booking
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/GiBi-develop/timerange/v1"
+)
+
+var base = time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+func hours(from, to int) timerange.TimeRange {
+	return timerange.TimeRange{
+		Start: base.Add(time.Duration(from) * time.Hour),
+		End:   base.Add(time.Duration(to) * time.Hour),
+	}
+}
+
+func newTestStore(t *testing.T, store Store, clock timerange.Clock) *ReservationStore {
+	t.Helper()
+	var seq atomic.Int64
+	s, err := NewReservationStore(store, Options{
+		Clock:       clock,
+		IDGenerator: func() string { return fmt.Sprintf("r%d", seq.Add(1)) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestReserve(t *testing.T) {
+	s := newTestStore(t, NewMemoryStore(), timerange.NewFakeClock(base))
+
+	if _, err := s.Reserve("room-1", hours(9, 10)); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("overlap is rejected", func(t *testing.T) {
+		_, err := s.Reserve("room-1", hours(9, 11))
+		var conflict *ConflictError
+		if !errors.As(err, &conflict) || !errors.Is(err, ErrConflict) {
+			t.Fatalf("Reserve() error = %v, want ConflictError", err)
+		}
+		if conflict.Existing.ID != "r1" {
+			t.Errorf("conflict with %q, want r1", conflict.Existing.ID)
+		}
+	})
+
+	t.Run("adjacent and other resources are allowed", func(t *testing.T) {
+		if _, err := s.Reserve("room-1", hours(10, 11)); err != nil {
+			t.Errorf("adjacent Reserve() error = %v", err)
+		}
+		if _, err := s.Reserve("room-2", hours(9, 10)); err != nil {
+			t.Errorf("other resource Reserve() error = %v", err)
+		}
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		if _, err := s.Reserve("room-1", hours(12, 12)); err != timerange.ErrInvalidRange {
+			t.Errorf("empty range error = %v, want ErrInvalidRange", err)
+		}
+		if _, err := s.Reserve("", hours(12, 13)); err != timerange.ErrInvalidArgument {
+			t.Errorf("empty resource error = %v, want ErrInvalidArgument", err)
+		}
+	})
+
+	if got := s.List("room-1"); len(got) != 2 || got[0].ID != "r1" {
+		t.Errorf("List() = %v, want r1 and the adjacent booking", got)
+	}
+}
+
+func TestHold(t *testing.T) {
+	clock := timerange.NewFakeClock(base)
+	s := newTestStore(t, NewMemoryStore(), clock)
+
+	hold, err := s.Hold("room-1", hours(9, 10), 15*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Reserve("room-1", hours(9, 10)); !errors.Is(err, ErrConflict) {
+		t.Errorf("Reserve() over active hold error = %v, want ErrConflict", err)
+	}
+
+	clock.Advance(20 * time.Minute)
+	if _, err := s.Confirm(hold.ID); err != ErrHoldExpired {
+		t.Errorf("Confirm() expired hold error = %v, want ErrHoldExpired", err)
+	}
+	if _, err := s.Reserve("room-1", hours(9, 10)); err != nil {
+		t.Errorf("Reserve() over expired hold error = %v", err)
+	}
+
+	n, err := s.Expire()
+	if err != nil || n != 1 {
+		t.Errorf("Expire() = %d, %v, want 1, nil", n, err)
+	}
+	if _, ok := s.Get(hold.ID); ok {
+		t.Error("expired hold is still stored")
+	}
+}
+
+func TestConfirmModifyCancel(t *testing.T) {
+	s := newTestStore(t, NewMemoryStore(), timerange.NewFakeClock(base))
+
+	hold, _ := s.Hold("room-1", hours(9, 10), time.Hour)
+	confirmed, err := s.Confirm(hold.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if confirmed.Status != StatusConfirmed || !confirmed.ExpiresAt.IsZero() {
+		t.Errorf("Confirm() = %+v, want confirmed without expiry", confirmed)
+	}
+
+	other, _ := s.Reserve("room-1", hours(12, 13))
+	if _, err := s.Modify(hold.ID, hours(11, 13)); !errors.Is(err, ErrConflict) {
+		t.Errorf("Modify() into other booking error = %v, want ErrConflict", err)
+	}
+	if _, err := s.Modify(hold.ID, hours(9, 12)); err != nil {
+		t.Errorf("Modify() error = %v", err)
+	}
+
+	if err := s.Cancel(other.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Cancel(other.ID); err != ErrNotFound {
+		t.Errorf("second Cancel() error = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Modify(hold.ID, hours(9, 14)); err != nil {
+		t.Errorf("Modify() after cancel error = %v", err)
+	}
+}
+
+func TestTransaction(t *testing.T) {
+	store := NewMemoryStore()
+	s := newTestStore(t, store, timerange.NewFakeClock(base))
+	existing, _ := s.Reserve("room-2", hours(9, 10))
+
+	t.Run("all or nothing", func(t *testing.T) {
+		err := s.Transaction(func(tx *Tx) error {
+			if _, err := tx.Reserve("room-1", hours(9, 10)); err != nil {
+				return err
+			}
+			_, err := tx.Reserve("room-2", hours(9, 10))
+			return err
+		})
+		if !errors.Is(err, ErrConflict) {
+			t.Fatalf("Transaction() error = %v, want ErrConflict", err)
+		}
+		if got := s.List("room-1"); len(got) != 0 {
+			t.Errorf("room-1 = %v, want no reservations after rollback", got)
+		}
+	})
+
+	t.Run("conflicts inside transaction", func(t *testing.T) {
+		err := s.Transaction(func(tx *Tx) error {
+			if _, err := tx.Reserve("room-3", hours(9, 11)); err != nil {
+				return err
+			}
+			_, err := tx.Reserve("room-3", hours(10, 12))
+			return err
+		})
+		if !errors.Is(err, ErrConflict) {
+			t.Errorf("Transaction() error = %v, want ErrConflict", err)
+		}
+	})
+
+	t.Run("move between resources", func(t *testing.T) {
+		err := s.Transaction(func(tx *Tx) error {
+			if err := tx.Cancel(existing.ID); err != nil {
+				return err
+			}
+			_, err := tx.Reserve("room-1", hours(9, 10))
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(s.List("room-1")) != 1 || len(s.List("room-2")) != 0 {
+			t.Errorf("room-1 = %v, room-2 = %v", s.List("room-1"), s.List("room-2"))
+		}
+	})
+
+	t.Run("state survives reload", func(t *testing.T) {
+		reloaded := newTestStore(t, store, timerange.NewFakeClock(base))
+		if got := reloaded.List("room-1"); len(got) != 1 {
+			t.Errorf("reloaded room-1 = %v, want 1 reservation", got)
+		}
+	})
+}
+
+type failingStore struct {
+	*MemoryStore
+}
+
+func (failingStore) Apply([]Change) error {
+	return errors.New("disk full")
+}
+
+func TestPersistenceFailure(t *testing.T) {
+	s := newTestStore(t, failingStore{NewMemoryStore()}, timerange.NewFakeClock(base))
+
+	if _, err := s.Reserve("room-1", hours(9, 10)); err == nil {
+		t.Fatal("Reserve() error = nil, want persistence error")
+	}
+	if got := s.List("room-1"); len(got) != 0 {
+		t.Errorf("List() = %v, want nothing when persistence fails", got)
+	}
+}
+
+func TestConcurrentReserve(t *testing.T) {
+	s := newTestStore(t, NewMemoryStore(), timerange.NewFakeClock(base))
+
+	var (
+		wg        sync.WaitGroup
+		successes atomic.Int64
+	)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Каждый интервал пересекается с соседними
+			if _, err := s.Reserve("room-1", hours(i%5, i%5+2)); err == nil {
+				successes.Add(1)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	list := s.List("room-1")
+	if int(successes.Load()) != len(list) {
+		t.Errorf("successes = %d, stored = %d", successes.Load(), len(list))
+	}
+	for i := 1; i < len(list); i++ {
+		if list[i-1].Range.Overlaps(list[i].Range) {
+			t.Errorf("overlapping reservations stored: %v and %v", list[i-1], list[i])
+		}
+	}
+}
@@ -0,0 +1,350 @@
+package booking
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	walFileName      = "reservations.wal"
+	snapshotFileName = "reservations.snapshot.json"
+
+	recordHeaderSize = 8
+	maxRecordSize    = 64 << 20
+)
+
+var ErrStoreClosed = errors.New("store is closed")
+
+type SyncPolicy int
+
+const (
+	// SyncAlways вызывает fsync после каждой записи в журнал.
+	SyncAlways SyncPolicy = iota
+	// SyncPeriodic вызывает fsync не реже FileOptions.SyncInterval.
+	SyncPeriodic
+	// SyncNever оставляет сброс на диск операционной системе.
+	SyncNever
+)
+
+type FileOptions struct {
+	Sync         SyncPolicy
+	SyncInterval time.Duration // для SyncPeriodic, по умолчанию 1s
+	CompactAfter int           // записей в журнале до снимка, по умолчанию 1000; < 0 - только вручную
+}
+
+// FileStore - реализация Store на файлах: журнал упреждающей записи и
+// периодический снимок состояния в JSON. Запись журнала - это длина и CRC32
+// пакета изменений, за которыми следует сам пакет; оборванный или поврежденный
+// хвост журнала отбрасывается при открытии.
+type FileStore struct {
+	mu      sync.Mutex
+	dir     string
+	opts    FileOptions
+	wal     *os.File
+	size    int64
+	records int
+	dirty   bool
+	state   map[string]Reservation
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func OpenFileStore(dir string, opts FileOptions) (*FileStore, error) {
+	if opts.SyncInterval <= 0 {
+		opts.SyncInterval = time.Second
+	}
+	if opts.CompactAfter == 0 {
+		opts.CompactAfter = 1000
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	s := &FileStore{dir: dir, opts: opts, state: make(map[string]Reservation)}
+	if err := s.loadSnapshot(); err != nil {
+		return nil, err
+	}
+
+	wal, err := os.OpenFile(filepath.Join(dir, walFileName), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	s.wal = wal
+	if err := s.recover(); err != nil {
+		wal.Close()
+		return nil, err
+	}
+
+	if opts.Sync == SyncPeriodic {
+		s.stop = make(chan struct{})
+		s.done = make(chan struct{})
+		go s.syncLoop()
+	}
+	return s, nil
+}
+
+func (s *FileStore) Load() ([]Reservation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.wal == nil {
+		return nil, ErrStoreClosed
+	}
+	return s.snapshotState(), nil
+}
+
+func (s *FileStore) Apply(changes []Change) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.wal == nil {
+		return ErrStoreClosed
+	}
+
+	payload, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+	record := make([]byte, recordHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(record[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(payload))
+	copy(record[recordHeaderSize:], payload)
+
+	if _, err := s.wal.WriteAt(record, s.size); err != nil {
+		// Не оставляем частично записанную запись в журнале
+		s.wal.Truncate(s.size)
+		return err
+	}
+	if s.opts.Sync == SyncAlways {
+		if err := s.wal.Sync(); err != nil {
+			s.wal.Truncate(s.size)
+			return err
+		}
+	}
+
+	s.size += int64(len(record))
+	s.records++
+	s.dirty = true
+	applyChanges(s.state, changes)
+
+	if s.opts.CompactAfter > 0 && s.records >= s.opts.CompactAfter {
+		// Изменения уже в журнале, поэтому ошибка снимка не отменяет запись:
+		// снимок будет повторен при следующем Apply или вызове Compact.
+		s.compact()
+	}
+	return nil
+}
+
+// Compact записывает снимок текущего состояния и очищает журнал.
+func (s *FileStore) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.wal == nil {
+		return ErrStoreClosed
+	}
+	return s.compact()
+}
+
+func (s *FileStore) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.wal == nil {
+		return ErrStoreClosed
+	}
+	s.dirty = false
+	return s.wal.Sync()
+}
+
+func (s *FileStore) Close() error {
+	// Повторный или параллельный Close ждет остановки фоновой синхронизации
+	s.stopOnce.Do(func() {
+		if s.stop != nil {
+			close(s.stop)
+			<-s.done
+		}
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.wal == nil {
+		return ErrStoreClosed
+	}
+	err := s.wal.Sync()
+	if cerr := s.wal.Close(); err == nil {
+		err = cerr
+	}
+	s.wal = nil
+	return err
+}
+
+// --- Recovery ---
+
+func (s *FileStore) loadSnapshot() error {
+	data, err := os.ReadFile(filepath.Join(s.dir, snapshotFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var reservations []Reservation
+	if err := json.Unmarshal(data, &reservations); err != nil {
+		return err
+	}
+	for _, r := range reservations {
+		s.state[r.ID] = r
+	}
+	return nil
+}
+
+// recover воспроизводит журнал поверх снимка. Журнал может содержать изменения,
+// уже вошедшие в снимок (сбой между записью снимка и очисткой журнала), -
+// повторное применение put/delete идемпотентно.
+func (s *FileStore) recover() error {
+	reader := bufio.NewReader(s.wal)
+	header := make([]byte, recordHeaderSize)
+	var offset int64
+
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				break
+			}
+			return err
+		}
+		size := binary.BigEndian.Uint32(header[0:4])
+		if size > maxRecordSize {
+			break
+		}
+		payload := make([]byte, size)
+		if _, err := io.ReadFull(reader, payload); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				break
+			}
+			return err
+		}
+		if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:8]) {
+			break
+		}
+		// Контрольная сумма сошлась, значит запись целая: ошибка разбора - это
+		// повреждение данных, а не оборванная запись, и молча ее отбрасывать нельзя.
+		var changes []Change
+		if err := json.Unmarshal(payload, &changes); err != nil {
+			return fmt.Errorf("wal record at offset %d: %w", offset, err)
+		}
+
+		applyChanges(s.state, changes)
+		offset += recordHeaderSize + int64(size)
+		s.records++
+	}
+
+	// Отбрасываем оборванный хвост, чтобы новые записи шли сразу за последней целой
+	if err := s.wal.Truncate(offset); err != nil {
+		return err
+	}
+	s.size = offset
+	return nil
+}
+
+// --- Helper Functions ---
+
+func (s *FileStore) compact() error {
+	data, err := json.Marshal(s.snapshotState())
+	if err != nil {
+		return err
+	}
+
+	tmp := filepath.Join(s.dir, snapshotFileName+".tmp")
+	if err := writeFileSync(tmp, data); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(s.dir, snapshotFileName)); err != nil {
+		return err
+	}
+	if err := syncDir(s.dir); err != nil {
+		return err
+	}
+
+	if err := s.wal.Truncate(0); err != nil {
+		return err
+	}
+	if err := s.wal.Sync(); err != nil {
+		return err
+	}
+	s.size = 0
+	s.records = 0
+	s.dirty = false
+	return nil
+}
+
+func (s *FileStore) snapshotState() []Reservation {
+	result := make([]Reservation, 0, len(s.state))
+	for _, r := range s.state {
+		result = append(result, r)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+func (s *FileStore) syncLoop() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.opts.SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			if s.dirty && s.wal != nil {
+				s.wal.Sync()
+				s.dirty = false
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+func writeFileSync(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
@@ -0,0 +1,228 @@
+package booking
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GiBi-develop/timerange/v1"
+)
+
+func openTestFileStore(t *testing.T, dir string, opts FileOptions) *FileStore {
+	t.Helper()
+	fs, err := OpenFileStore(dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fs
+}
+
+// reopenStore использует случайные идентификаторы, чтобы новые брони не
+// совпали с восстановленными.
+func reopenStore(t *testing.T, fs *FileStore) *ReservationStore {
+	t.Helper()
+	s, err := NewReservationStore(fs, Options{Clock: timerange.NewFakeClock(base)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func walSize(t *testing.T, dir string) int64 {
+	t.Helper()
+	info, err := os.Stat(filepath.Join(dir, walFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info.Size()
+}
+
+func TestFileStorePersistence(t *testing.T) {
+	dir := t.TempDir()
+	fs := openTestFileStore(t, dir, FileOptions{})
+	s := newTestStore(t, fs, timerange.NewFakeClock(base))
+
+	first, _ := s.Reserve("room-1", hours(9, 10))
+	second, _ := s.Hold("room-1", hours(11, 12), time.Hour)
+	if _, err := s.Modify(first.ID, hours(8, 10)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Cancel(second.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened := openTestFileStore(t, dir, FileOptions{})
+	defer reopened.Close()
+	list := reopenStore(t, reopened).List("room-1")
+	if len(list) != 1 || !list[0].Range.Equal(hours(8, 10)) || list[0].Status != StatusConfirmed {
+		t.Errorf("restored = %+v, want single confirmed 08:00-10:00", list)
+	}
+}
+
+func TestFileStoreTornWrites(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(t *testing.T, path string, lastRecord int64)
+	}{
+		{
+			name: "truncated payload",
+			corrupt: func(t *testing.T, path string, lastRecord int64) {
+				info, _ := os.Stat(path)
+				if err := os.Truncate(path, info.Size()-3); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "truncated header",
+			corrupt: func(t *testing.T, path string, lastRecord int64) {
+				if err := os.Truncate(path, lastRecord+5); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "corrupted checksum",
+			corrupt: func(t *testing.T, path string, lastRecord int64) {
+				f, err := os.OpenFile(path, os.O_RDWR, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer f.Close()
+				if _, err := f.WriteAt([]byte{0xFF, 0xFF}, lastRecord+recordHeaderSize+2); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "garbage tail",
+			corrupt: func(t *testing.T, path string, lastRecord int64) {
+				f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer f.Close()
+				f.Write([]byte{0x00, 0x00, 0x10})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			fs := openTestFileStore(t, dir, FileOptions{})
+			s := newTestStore(t, fs, timerange.NewFakeClock(base))
+
+			if _, err := s.Reserve("room-1", hours(9, 10)); err != nil {
+				t.Fatal(err)
+			}
+			lastRecord := walSize(t, dir)
+			if _, err := s.Reserve("room-1", hours(11, 12)); err != nil {
+				t.Fatal(err)
+			}
+			fs.Close()
+
+			path := filepath.Join(dir, walFileName)
+			tt.corrupt(t, path, lastRecord)
+
+			reopened := openTestFileStore(t, dir, FileOptions{})
+			restored := reopenStore(t, reopened)
+
+			list := restored.List("room-1")
+			wantCount := 1
+			if tt.name == "garbage tail" {
+				wantCount = 2
+			}
+			if len(list) != wantCount || !list[0].Range.Equal(hours(9, 10)) {
+				t.Fatalf("recovered = %+v, want %d reservations starting at 09:00", list, wantCount)
+			}
+
+			// После восстановления журнал снова пригоден для записи
+			if _, err := restored.Reserve("room-1", hours(13, 14)); err != nil {
+				t.Fatal(err)
+			}
+			reopened.Close()
+
+			again := openTestFileStore(t, dir, FileOptions{})
+			defer again.Close()
+			if got := reopenStore(t, again).List("room-1"); len(got) != wantCount+1 {
+				t.Errorf("after second reopen = %+v, want %d reservations", got, wantCount+1)
+			}
+		})
+	}
+}
+
+func TestFileStoreCompaction(t *testing.T) {
+	dir := t.TempDir()
+	fs := openTestFileStore(t, dir, FileOptions{Sync: SyncNever, CompactAfter: 3})
+	s := newTestStore(t, fs, timerange.NewFakeClock(base))
+
+	for i := 0; i < 4; i++ {
+		if _, err := s.Reserve("room-1", hours(i, i+1)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, snapshotFileName)); err != nil {
+		t.Fatalf("snapshot not written: %v", err)
+	}
+	if records := fs.records; records != 1 {
+		t.Errorf("records after compaction = %d, want 1", records)
+	}
+	fs.Close()
+
+	reopened := openTestFileStore(t, dir, FileOptions{})
+	defer reopened.Close()
+	if got := reopenStore(t, reopened).List("room-1"); len(got) != 4 {
+		t.Errorf("restored = %d reservations, want 4", len(got))
+	}
+}
+
+func TestFileStorePeriodicSync(t *testing.T) {
+	fs := openTestFileStore(t, t.TempDir(), FileOptions{Sync: SyncPeriodic, SyncInterval: time.Millisecond})
+	s := newTestStore(t, fs, timerange.NewFakeClock(base))
+
+	if _, err := s.Reserve("room-1", hours(9, 10)); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Apply(nil); err != ErrStoreClosed {
+		t.Errorf("Apply() after Close error = %v, want ErrStoreClosed", err)
+	}
+}
+
+func TestFileStoreConcurrentClose(t *testing.T) {
+	fs := openTestFileStore(t, t.TempDir(), FileOptions{Sync: SyncPeriodic, SyncInterval: time.Millisecond})
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		closed int
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := fs.Close()
+			if err != nil && err != ErrStoreClosed {
+				t.Errorf("Close() error = %v", err)
+			}
+			if err == nil {
+				mu.Lock()
+				closed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if closed != 1 {
+		t.Errorf("Close() succeeded %d times, want 1", closed)
+	}
+}
@@ -0,0 +1,134 @@
+package booking
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Store - подключаемое хранилище бронирований. Apply должен применять пакет
+// изменений атомарно: либо все, либо ни одного.
+type Store interface {
+	Load() ([]Reservation, error)
+	Apply(changes []Change) error
+}
+
+type Op int
+
+const (
+	OpPut Op = iota + 1
+	OpDelete
+)
+
+func (o Op) String() string {
+	switch o {
+	case OpPut:
+		return "put"
+	case OpDelete:
+		return "delete"
+	default:
+		return "unknown"
+	}
+}
+
+func (o Op) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+func (o *Op) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "put":
+		*o = OpPut
+	case "delete":
+		*o = OpDelete
+	default:
+		return fmt.Errorf("unknown change op %q", text)
+	}
+	return nil
+}
+
+// Change - одно изменение в пакете. Для OpDelete используется только Reservation.ID.
+type Change struct {
+	Op          Op
+	Reservation Reservation
+}
+
+// MarshalJSON не сериализует пустой интервал удаляемой брони:
+// TimeRange отказывается разбирать нулевой интервал.
+func (c Change) MarshalJSON() ([]byte, error) {
+	aux := struct {
+		Op          Op           `json:"op"`
+		ID          string       `json:"id"`
+		Reservation *Reservation `json:"reservation,omitempty"`
+	}{
+		Op: c.Op,
+		ID: c.Reservation.ID,
+	}
+	if c.Op == OpPut {
+		aux.Reservation = &c.Reservation
+	}
+	return json.Marshal(aux)
+}
+
+func (c *Change) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Op          Op           `json:"op"`
+		ID          string       `json:"id"`
+		Reservation *Reservation `json:"reservation"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	c.Op = aux.Op
+	c.Reservation = Reservation{ID: aux.ID}
+	if aux.Reservation != nil {
+		c.Reservation = *aux.Reservation
+	}
+	return nil
+}
+
+// --- In-Memory Store ---
+
+type MemoryStore struct {
+	mu           sync.Mutex
+	reservations map[string]Reservation
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{reservations: make(map[string]Reservation)}
+}
+
+func (s *MemoryStore) Load() ([]Reservation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]Reservation, 0, len(s.reservations))
+	for _, r := range s.reservations {
+		result = append(result, r)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+func (s *MemoryStore) Apply(changes []Change) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	applyChanges(s.reservations, changes)
+	return nil
+}
+
+func applyChanges(reservations map[string]Reservation, changes []Change) {
+	for _, c := range changes {
+		switch c.Op {
+		case OpPut:
+			reservations[c.Reservation.ID] = c.Reservation
+		case OpDelete:
+			delete(reservations, c.Reservation.ID)
+		}
+	}
+}
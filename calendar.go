@@ -0,0 +1,116 @@
+package timerange
+
+import (
+	"slices"
+	"time"
+)
+
+// CalendarUnit - календарная единица для разбиения и навигации.
+type CalendarUnit int
+
+const (
+	UnitDay CalendarUnit = iota + 1
+	UnitWeek
+	UnitMonth
+	UnitQuarter
+	UnitYear
+)
+
+func (u CalendarUnit) String() string {
+	switch u {
+	case UnitDay:
+		return "day"
+	case UnitWeek:
+		return "week"
+	case UnitMonth:
+		return "month"
+	case UnitQuarter:
+		return "quarter"
+	case UnitYear:
+		return "year"
+	default:
+		return "unknown"
+	}
+}
+
+func (u CalendarUnit) valid() bool {
+	return u >= UnitDay && u <= UnitYear
+}
+
+// --- Calendar Splitting ---
+
+// SplitByCalendar делит интервал по границам календарных единиц в loc
+// (недели начинаются с понедельника). При nil loc используется зона tr.Start.
+func (tr TimeRange) SplitByCalendar(unit CalendarUnit, loc *time.Location) []TimeRange {
+	return slices.Collect(tr.SplitByCalendarSeq(unit, loc))
+}
+
+// --- Helper Functions ---
+
+// startOfUnit возвращает начало единицы, содержащей t, в зоне loc.
+func startOfUnit(t time.Time, unit CalendarUnit, loc *time.Location, weekStart time.Weekday) time.Time {
+	if loc == nil {
+		loc = t.Location()
+	}
+	t = t.In(loc)
+	year, month, day := t.Date()
+
+	switch unit {
+	case UnitWeek:
+		offset := (int(t.Weekday()) - int(weekStart) + 7) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, loc)
+	case UnitMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	case UnitQuarter:
+		return time.Date(year, month-(month-1)%3, 1, 0, 0, 0, 0, loc)
+	case UnitYear:
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, loc)
+	}
+}
+
+// addUnits сдвигает начало единицы на n единиц по календарю, а не по длительности,
+// поэтому переходы на летнее время не смещают полночь.
+func addUnits(t time.Time, unit CalendarUnit, n int) time.Time {
+	switch unit {
+	case UnitWeek:
+		return t.AddDate(0, 0, 7*n)
+	case UnitMonth:
+		return t.AddDate(0, n, 0)
+	case UnitQuarter:
+		return t.AddDate(0, 3*n, 0)
+	case UnitYear:
+		return t.AddDate(n, 0, 0)
+	default:
+		return t.AddDate(0, 0, n)
+	}
+}
+
+// addUnitsClamped сдвигает произвольный момент на n единиц, прижимая день
+// к концу месяца: 31 марта минус месяц - это 29 февраля, а не 2 марта.
+func addUnitsClamped(t time.Time, unit CalendarUnit, n int) time.Time {
+	var months int
+	switch unit {
+	case UnitMonth:
+		months = n
+	case UnitQuarter:
+		months = 3 * n
+	case UnitYear:
+		months = 12 * n
+	default:
+		return addUnits(t, unit, n)
+	}
+
+	year, month, day := t.Date()
+	hour, minute, sec := t.Clock()
+	first := time.Date(year, month+time.Month(months), 1, 0, 0, 0, 0, t.Location())
+	if last := daysIn(first.Year(), first.Month()); day > last {
+		day = last
+	}
+	return time.Date(first.Year(), first.Month(), day, hour, minute, sec, t.Nanosecond(), t.Location())
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
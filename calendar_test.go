@@ -0,0 +1,64 @@
+package timerange
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSplitByCalendar(t *testing.T) {
+	t.Run("months", func(t *testing.T) {
+		tr := TimeRange{
+			Start: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC),
+			End:   time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+		}
+
+		result := tr.SplitByCalendar(UnitMonth, time.UTC)
+		expected := []TimeRange{
+			{Start: tr.Start, End: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+			{Start: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+			{Start: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), End: tr.End},
+		}
+		if !compareRanges(result, expected) {
+			t.Errorf("SplitByCalendar() = %v, want %v", result, expected)
+		}
+	})
+
+	t.Run("weeks start on monday", func(t *testing.T) {
+		tr := TimeRange{
+			Start: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), // среда
+			End:   time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+		}
+
+		result := tr.SplitByCalendar(UnitWeek, time.UTC)
+		if len(result) != 2 || !result[0].End.Equal(time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("SplitByCalendar() = %v, want split at 2024-01-08", result)
+		}
+	})
+
+	t.Run("days across DST change", func(t *testing.T) {
+		loc, err := time.LoadLocation("Europe/Berlin")
+		if err != nil {
+			t.Skip("time zone data unavailable")
+		}
+		tr := TimeRange{
+			Start: time.Date(2024, 3, 30, 0, 0, 0, 0, loc),
+			End:   time.Date(2024, 4, 1, 0, 0, 0, 0, loc),
+		}
+
+		result := tr.SplitByCalendar(UnitDay, loc)
+		if len(result) != 2 {
+			t.Fatalf("SplitByCalendar() len = %d, want 2", len(result))
+		}
+		if result[1].Duration() != 23*time.Hour {
+			t.Errorf("DST day duration = %v, want 23h", result[1].Duration())
+		}
+	})
+
+	t.Run("invalid unit", func(t *testing.T) {
+		tr := hourRange(0, 5)
+		result := tr.SplitByCalendar(CalendarUnit(0), time.UTC)
+		if !compareRanges(result, []TimeRange{tr}) {
+			t.Errorf("SplitByCalendar() = %v, want [%v]", result, tr)
+		}
+	})
+}
@@ -0,0 +1,149 @@
+package timerange
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock - источник текущего времени. В тестах вместо SystemClock подставляется FakeClock.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+var SystemClock Clock = systemClock{}
+
+// FakeClock - управляемые часы для тестов, безопасные для конкурентного использования.
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *FakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// --- Relative Presets ---
+
+func Today(clock Clock, loc *time.Location) TimeRange {
+	return currentUnit(clock, loc, UnitDay, time.Monday)
+}
+
+func Yesterday(clock Clock, loc *time.Location) TimeRange {
+	return previousUnit(clock, loc, UnitDay, time.Monday)
+}
+
+// ThisWeek возвращает текущую неделю; weekStart задает первый день недели.
+func ThisWeek(clock Clock, loc *time.Location, weekStart time.Weekday) TimeRange {
+	return currentUnit(clock, loc, UnitWeek, weekStart)
+}
+
+func PreviousWeek(clock Clock, loc *time.Location, weekStart time.Weekday) TimeRange {
+	return previousUnit(clock, loc, UnitWeek, weekStart)
+}
+
+func ThisMonth(clock Clock, loc *time.Location) TimeRange {
+	return currentUnit(clock, loc, UnitMonth, time.Monday)
+}
+
+func PreviousMonth(clock Clock, loc *time.Location) TimeRange {
+	return previousUnit(clock, loc, UnitMonth, time.Monday)
+}
+
+func ThisQuarter(clock Clock, loc *time.Location) TimeRange {
+	return currentUnit(clock, loc, UnitQuarter, time.Monday)
+}
+
+func PreviousQuarter(clock Clock, loc *time.Location) TimeRange {
+	return previousUnit(clock, loc, UnitQuarter, time.Monday)
+}
+
+func ThisYear(clock Clock, loc *time.Location) TimeRange {
+	return currentUnit(clock, loc, UnitYear, time.Monday)
+}
+
+func PreviousYear(clock Clock, loc *time.Location) TimeRange {
+	return previousUnit(clock, loc, UnitYear, time.Monday)
+}
+
+func WeekToDate(clock Clock, loc *time.Location, weekStart time.Weekday) TimeRange {
+	return unitToDate(clock, loc, UnitWeek, weekStart)
+}
+
+func MonthToDate(clock Clock, loc *time.Location) TimeRange {
+	return unitToDate(clock, loc, UnitMonth, time.Monday)
+}
+
+func QuarterToDate(clock Clock, loc *time.Location) TimeRange {
+	return unitToDate(clock, loc, UnitQuarter, time.Monday)
+}
+
+func YearToDate(clock Clock, loc *time.Location) TimeRange {
+	return unitToDate(clock, loc, UnitYear, time.Monday)
+}
+
+// LastN возвращает скользящее окно из n календарных единиц, заканчивающееся
+// текущим моментом: "последние 7 дней" - это [now-7d, now) по календарю loc.
+func LastN(clock Clock, loc *time.Location, unit CalendarUnit, n int) (TimeRange, error) {
+	if !unit.valid() || n < 0 {
+		return TimeRange{}, ErrInvalidArgument
+	}
+	now := inLocation(clock.Now(), loc)
+	return New(addUnitsClamped(now, unit, -n), now)
+}
+
+// LastNComplete возвращает n завершенных единиц, предшествующих текущей.
+func LastNComplete(clock Clock, loc *time.Location, unit CalendarUnit, n int, weekStart time.Weekday) (TimeRange, error) {
+	if !unit.valid() || n < 0 {
+		return TimeRange{}, ErrInvalidArgument
+	}
+	end := startOfUnit(clock.Now(), unit, loc, weekStart)
+	return New(addUnits(end, unit, -n), end)
+}
+
+// --- Helper Functions ---
+
+func currentUnit(clock Clock, loc *time.Location, unit CalendarUnit, weekStart time.Weekday) TimeRange {
+	start := startOfUnit(clock.Now(), unit, loc, weekStart)
+	return TimeRange{Start: start, End: addUnits(start, unit, 1)}
+}
+
+func previousUnit(clock Clock, loc *time.Location, unit CalendarUnit, weekStart time.Weekday) TimeRange {
+	end := startOfUnit(clock.Now(), unit, loc, weekStart)
+	return TimeRange{Start: addUnits(end, unit, -1), End: end}
+}
+
+func unitToDate(clock Clock, loc *time.Location, unit CalendarUnit, weekStart time.Weekday) TimeRange {
+	now := inLocation(clock.Now(), loc)
+	return TimeRange{Start: startOfUnit(now, unit, loc, weekStart), End: now}
+}
+
+func inLocation(t time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		return t
+	}
+	return t.In(loc)
+}
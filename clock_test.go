@@ -0,0 +1,124 @@
+package timerange
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2024, 5, 15, 10, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	clock.Advance(time.Hour)
+	if !clock.Now().Equal(start.Add(time.Hour)) {
+		t.Errorf("Now() = %v, want %v", clock.Now(), start.Add(time.Hour))
+	}
+	clock.Set(start)
+	if !clock.Now().Equal(start) {
+		t.Errorf("Now() = %v, want %v", clock.Now(), start)
+	}
+}
+
+func TestPresets(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	// 2024-05-15 23:30 UTC - уже четверг 16 мая в UTC+3
+	clock := NewFakeClock(time.Date(2024, 5, 15, 23, 30, 0, 0, time.UTC))
+	at := func(y int, m time.Month, d, h, min int) time.Time {
+		return time.Date(y, m, d, h, min, 0, 0, loc)
+	}
+
+	tests := []struct {
+		name   string
+		got    TimeRange
+		expect TimeRange
+	}{
+		{
+			name:   "today",
+			got:    Today(clock, loc),
+			expect: TimeRange{Start: at(2024, 5, 16, 0, 0), End: at(2024, 5, 17, 0, 0)},
+		},
+		{
+			name:   "yesterday",
+			got:    Yesterday(clock, loc),
+			expect: TimeRange{Start: at(2024, 5, 15, 0, 0), End: at(2024, 5, 16, 0, 0)},
+		},
+		{
+			name:   "this week from monday",
+			got:    ThisWeek(clock, loc, time.Monday),
+			expect: TimeRange{Start: at(2024, 5, 13, 0, 0), End: at(2024, 5, 20, 0, 0)},
+		},
+		{
+			name:   "this week from sunday",
+			got:    ThisWeek(clock, loc, time.Sunday),
+			expect: TimeRange{Start: at(2024, 5, 12, 0, 0), End: at(2024, 5, 19, 0, 0)},
+		},
+		{
+			name:   "month to date",
+			got:    MonthToDate(clock, loc),
+			expect: TimeRange{Start: at(2024, 5, 1, 0, 0), End: at(2024, 5, 16, 2, 30)},
+		},
+		{
+			name:   "previous quarter",
+			got:    PreviousQuarter(clock, loc),
+			expect: TimeRange{Start: at(2024, 1, 1, 0, 0), End: at(2024, 4, 1, 0, 0)},
+		},
+		{
+			name:   "year to date",
+			got:    YearToDate(clock, loc),
+			expect: TimeRange{Start: at(2024, 1, 1, 0, 0), End: at(2024, 5, 16, 2, 30)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.got.Equal(tt.expect) {
+				t.Errorf("got %v, want %v", tt.got, tt.expect)
+			}
+		})
+	}
+}
+
+func TestLastN(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC))
+
+	t.Run("rolling days", func(t *testing.T) {
+		tr, err := LastN(clock, time.UTC, UnitDay, 7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tr.Duration() != 7*24*time.Hour {
+			t.Errorf("LastN() duration = %v, want 168h", tr.Duration())
+		}
+	})
+
+	t.Run("month is clamped to month end", func(t *testing.T) {
+		tr, err := LastN(clock, time.UTC, UnitMonth, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC)
+		if !tr.Start.Equal(expected) {
+			t.Errorf("LastN() start = %v, want %v", tr.Start, expected)
+		}
+	})
+
+	t.Run("complete weeks", func(t *testing.T) {
+		tr, err := LastNComplete(clock, time.UTC, UnitWeek, 2, time.Monday)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := TimeRange{
+			Start: time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2024, 3, 25, 0, 0, 0, 0, time.UTC),
+		}
+		if !tr.Equal(expected) {
+			t.Errorf("LastNComplete() = %v, want %v", tr, expected)
+		}
+	})
+
+	t.Run("invalid unit", func(t *testing.T) {
+		if _, err := LastN(clock, time.UTC, CalendarUnit(42), 1); err != ErrInvalidArgument {
+			t.Errorf("LastN() error = %v, want ErrInvalidArgument", err)
+		}
+	})
+}
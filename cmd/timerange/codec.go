@@ -0,0 +1,331 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/GiBi-develop/timerange/v1"
+)
+
+const (
+	formatISO  = "iso"
+	formatJSON = "json"
+	formatCSV  = "csv"
+	formatICal = "ical"
+)
+
+var errUnknownFormat = errors.New("unknown format")
+
+// localLayouts - форматы времени без смещения, которые интерпретируются в зоне -in-tz.
+var localLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// --- Reading ---
+
+func readRanges(r io.Reader, format string, loc *time.Location) ([]timerange.TimeRange, error) {
+	switch format {
+	case formatISO:
+		return readISO(r, loc)
+	case formatJSON:
+		return readJSON(r)
+	case formatCSV:
+		return readCSV(r, loc)
+	case formatICal:
+		return readICal(r, loc)
+	default:
+		return nil, fmt.Errorf("%w: %q", errUnknownFormat, format)
+	}
+}
+
+func readISO(r io.Reader, loc *time.Location) ([]timerange.TimeRange, error) {
+	var ranges []timerange.TimeRange
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		start, end, ok := strings.Cut(text, "/")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected start/end, got %q", line, text)
+		}
+		tr, err := parseRange(start, end, loc)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		ranges = append(ranges, tr)
+	}
+	return ranges, scanner.Err()
+}
+
+// readJSON принимает как массив, так и поток объектов формата MarshalJSON.
+func readJSON(r io.Reader) ([]timerange.TimeRange, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var ranges []timerange.TimeRange
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &ranges); err != nil {
+			return nil, err
+		}
+		return ranges, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var tr timerange.TimeRange
+		if err := dec.Decode(&tr); err == io.EOF {
+			return ranges, nil
+		} else if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, tr)
+	}
+}
+
+func readCSV(r io.Reader, loc *time.Location) ([]timerange.TimeRange, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var ranges []timerange.TimeRange
+	for i, record := range records {
+		if len(record) < 2 {
+			return nil, fmt.Errorf("record %d: expected start,end", i+1)
+		}
+		if i == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "start") {
+			continue
+		}
+		tr, err := parseRange(record[0], record[1], loc)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", i+1, err)
+		}
+		ranges = append(ranges, tr)
+	}
+	return ranges, nil
+}
+
+// readICal извлекает DTSTART/DTEND из компонентов VEVENT.
+func readICal(r io.Reader, loc *time.Location) ([]timerange.TimeRange, error) {
+	lines, err := unfoldICal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		ranges     []timerange.TimeRange
+		inEvent    bool
+		start, end time.Time
+		allDay     bool
+	)
+	for _, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		prop, params, _ := strings.Cut(name, ";")
+
+		switch strings.ToUpper(prop) {
+		case "BEGIN":
+			if strings.EqualFold(value, "VEVENT") {
+				inEvent, start, end, allDay = true, time.Time{}, time.Time{}, false
+			}
+		case "END":
+			if !strings.EqualFold(value, "VEVENT") || !inEvent {
+				continue
+			}
+			inEvent = false
+			if start.IsZero() {
+				return nil, errors.New("VEVENT without DTSTART")
+			}
+			if end.IsZero() {
+				if !allDay {
+					return nil, errors.New("VEVENT without DTEND")
+				}
+				end = start.AddDate(0, 0, 1)
+			}
+			tr, err := timerange.New(start, end)
+			if err != nil {
+				return nil, err
+			}
+			ranges = append(ranges, tr)
+		case "DTSTART", "DTEND":
+			if !inEvent {
+				continue
+			}
+			t, date, err := parseICalTime(value, params, loc)
+			if err != nil {
+				return nil, err
+			}
+			if strings.EqualFold(prop, "DTSTART") {
+				start, allDay = t, date
+			} else {
+				end = t
+			}
+		}
+	}
+	return ranges, nil
+}
+
+func unfoldICal(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+func parseICalTime(value, params string, loc *time.Location) (time.Time, bool, error) {
+	for _, param := range strings.Split(params, ";") {
+		key, val, _ := strings.Cut(param, "=")
+		switch strings.ToUpper(key) {
+		case "TZID":
+			zone, err := time.LoadLocation(val)
+			if err != nil {
+				return time.Time{}, false, err
+			}
+			loc = zone
+		case "VALUE":
+			if strings.EqualFold(val, "DATE") {
+				t, err := time.ParseInLocation("20060102", value, loc)
+				return t, true, err
+			}
+		}
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+func parseRange(start, end string, loc *time.Location) (timerange.TimeRange, error) {
+	s, err := parseInstant(start, loc)
+	if err != nil {
+		return timerange.TimeRange{}, err
+	}
+	e, err := parseInstant(end, loc)
+	if err != nil {
+		return timerange.TimeRange{}, err
+	}
+	return timerange.New(s, e)
+}
+
+func parseInstant(s string, loc *time.Location) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range localLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse time %q", s)
+}
+
+// --- Writing ---
+
+func writeRanges(w io.Writer, format string, ranges []timerange.TimeRange, loc *time.Location) error {
+	if loc != nil {
+		converted := make([]timerange.TimeRange, len(ranges))
+		for i, tr := range ranges {
+			converted[i] = timerange.TimeRange{Start: tr.Start.In(loc), End: tr.End.In(loc)}
+		}
+		ranges = converted
+	}
+
+	switch format {
+	case formatISO:
+		return writeISO(w, ranges)
+	case formatJSON:
+		return writeJSON(w, ranges)
+	case formatCSV:
+		return writeCSV(w, ranges)
+	case formatICal:
+		return writeICal(w, ranges)
+	default:
+		return fmt.Errorf("%w: %q", errUnknownFormat, format)
+	}
+}
+
+func writeISO(w io.Writer, ranges []timerange.TimeRange) error {
+	bw := bufio.NewWriter(w)
+	for _, tr := range ranges {
+		fmt.Fprintln(bw, tr.ToISOString())
+	}
+	return bw.Flush()
+}
+
+func writeJSON(w io.Writer, ranges []timerange.TimeRange) error {
+	if ranges == nil {
+		ranges = []timerange.TimeRange{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(ranges)
+}
+
+func writeCSV(w io.Writer, ranges []timerange.TimeRange) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"start", "end"}); err != nil {
+		return err
+	}
+	for _, tr := range ranges {
+		record := []string{tr.Start.Format(time.RFC3339), tr.End.Format(time.RFC3339)}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeICal всегда пишет время в UTC: VTIMEZONE не генерируется.
+func writeICal(w io.Writer, ranges []timerange.TimeRange) error {
+	const layout = "20060102T150405Z"
+
+	bw := bufio.NewWriter(w)
+	line := func(s string) { bw.WriteString(s + "\r\n") }
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//GiBi-develop//timerange//EN")
+	for i, tr := range ranges {
+		line("BEGIN:VEVENT")
+		line(fmt.Sprintf("UID:%s-%d@timerange", tr.ToSlugString(), i+1))
+		line("DTSTAMP:" + tr.Start.UTC().Format(layout))
+		line("DTSTART:" + tr.Start.UTC().Format(layout))
+		line("DTEND:" + tr.End.UTC().Format(layout))
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return bw.Flush()
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GiBi-develop/timerange/v1"
+)
+
+func TestCodecRoundTrip(t *testing.T) {
+	ranges := []timerange.TimeRange{
+		{Start: time.Date(2023, 1, 1, 9, 0, 0, 0, time.UTC), End: time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{Start: time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC), End: time.Date(2023, 1, 2, 11, 30, 0, 0, time.UTC)},
+	}
+
+	for _, format := range []string{formatISO, formatJSON, formatCSV, formatICal} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeRanges(&buf, format, ranges, nil); err != nil {
+				t.Fatal(err)
+			}
+			parsed, err := readRanges(&buf, format, time.UTC)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(parsed) != len(ranges) {
+				t.Fatalf("len = %d, want %d", len(parsed), len(ranges))
+			}
+			for i := range ranges {
+				if !parsed[i].Equal(ranges[i]) {
+					t.Errorf("range %d = %v, want %v", i, parsed[i], ranges[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReadICal(t *testing.T) {
+	input := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"SUMMARY:Maintenance",
+		"DTSTART;TZID=Europe/Moscow:20230101T020000",
+		"DTEND;TZID=Europe/Moscow:20230101T",
+		" 050000",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20230105",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	ranges, err := readICal(strings.NewReader(input), time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ranges) != 2 {
+		t.Fatalf("len = %d, want 2", len(ranges))
+	}
+	if ranges[0].Duration() != 3*time.Hour || ranges[0].Start.UTC().Hour() != 23 {
+		t.Errorf("event 1 = %v, want 3h starting 23:00 UTC", ranges[0])
+	}
+	if ranges[1].Duration() != 24*time.Hour {
+		t.Errorf("all-day event duration = %v, want 24h", ranges[1].Duration())
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	if _, err := readRanges(strings.NewReader("garbage\n"), formatISO, time.UTC); err == nil {
+		t.Error("readRanges(iso) error = nil, want error")
+	}
+	if _, err := readRanges(strings.NewReader(""), "xml", time.UTC); err == nil {
+		t.Error("readRanges(xml) error = nil, want error")
+	}
+}
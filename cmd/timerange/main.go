@@ -0,0 +1,183 @@
+// Command timerange выполняет арифметику временных интервалов в командной строке.
+//
+//	timerange union     [flags] [file...]
+//	timerange intersect [flags] [file...]
+//	timerange subtract  [flags] -minus file [file...]
+//	timerange gaps      [flags] -bounds start/end [file...]
+//	timerange split     [flags] (-every 1h | -by day) [file...]
+//	timerange format    [flags] [file...]
+//
+// Интервалы читаются из файлов или stdin ("-") в форматах iso, json, csv и ical.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/GiBi-develop/timerange/v1"
+)
+
+var calendarUnits = map[string]timerange.CalendarUnit{
+	"day":     timerange.UnitDay,
+	"week":    timerange.UnitWeek,
+	"month":   timerange.UnitMonth,
+	"quarter": timerange.UnitQuarter,
+	"year":    timerange.UnitYear,
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "timerange:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		usage(stderr)
+		return errors.New("missing command")
+	}
+
+	cmd, args := args[0], args[1:]
+	fs := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var (
+		in     = fs.String("in", formatISO, "input format: iso, json, csv, ical")
+		out    = fs.String("out", "", "output format (default: same as -in)")
+		tz     = fs.String("tz", "", "time zone for output, e.g. Europe/Moscow")
+		inTZ   = fs.String("in-tz", "UTC", "time zone for input times without offset")
+		minus  = fs.String("minus", "", "subtract: file with ranges to subtract")
+		bounds = fs.String("bounds", "", "gaps: bounding range as start/end")
+		every  = fs.Duration("every", 0, "split: part duration")
+		by     = fs.String("by", "", "split: calendar unit (day, week, month, quarter, year)")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *out == "" {
+		*out = *in
+	}
+
+	inLoc, err := time.LoadLocation(*inTZ)
+	if err != nil {
+		return err
+	}
+	var outLoc *time.Location
+	if *tz != "" {
+		if outLoc, err = time.LoadLocation(*tz); err != nil {
+			return err
+		}
+	}
+
+	ranges, err := readInputs(fs.Args(), stdin, *in, inLoc)
+	if err != nil {
+		return err
+	}
+
+	var result []timerange.TimeRange
+	switch cmd {
+	case "union":
+		if len(ranges) > 0 {
+			result, err = timerange.Union(ranges)
+		}
+	case "intersect":
+		var common timerange.TimeRange
+		common, err = timerange.Intersection(ranges)
+		result = []timerange.TimeRange{common}
+	case "subtract":
+		if *minus == "" {
+			return errors.New("subtract: -minus is required")
+		}
+		var subtrahend []timerange.TimeRange
+		if subtrahend, err = readInputs([]string{*minus}, stdin, *in, inLoc); err == nil {
+			result = subtractAll(ranges, subtrahend)
+		}
+	case "gaps":
+		if *bounds == "" {
+			return errors.New("gaps: -bounds is required")
+		}
+		start, end, _ := strings.Cut(*bounds, "/")
+		var b timerange.TimeRange
+		if b, err = parseRange(start, end, inLoc); err == nil {
+			result, err = timerange.FindGaps(ranges, b)
+		}
+	case "split":
+		result, err = split(ranges, *every, *by, inLoc)
+	case "format":
+		result = ranges
+	default:
+		usage(stderr)
+		return fmt.Errorf("unknown command %q", cmd)
+	}
+	if err != nil {
+		return err
+	}
+
+	return writeRanges(stdout, *out, result, outLoc)
+}
+
+func readInputs(paths []string, stdin io.Reader, format string, loc *time.Location) ([]timerange.TimeRange, error) {
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+
+	var ranges []timerange.TimeRange
+	for _, path := range paths {
+		parsed, err := readFile(path, stdin, format, loc)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		ranges = append(ranges, parsed...)
+	}
+	return ranges, nil
+}
+
+func readFile(path string, stdin io.Reader, format string, loc *time.Location) ([]timerange.TimeRange, error) {
+	if path == "-" {
+		return readRanges(stdin, format, loc)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readRanges(f, format, loc)
+}
+
+func subtractAll(ranges, subtrahend []timerange.TimeRange) []timerange.TimeRange {
+	var result []timerange.TimeRange
+	for _, tr := range ranges {
+		result = append(result, tr.SubtractAll(subtrahend)...)
+	}
+	return result
+}
+
+func split(ranges []timerange.TimeRange, every time.Duration, by string, loc *time.Location) ([]timerange.TimeRange, error) {
+	if (every > 0) == (by != "") {
+		return nil, errors.New("split: exactly one of -every or -by is required")
+	}
+
+	var result []timerange.TimeRange
+	for _, tr := range ranges {
+		if every > 0 {
+			result = append(result, tr.SplitByDuration(every)...)
+			continue
+		}
+		unit, ok := calendarUnits[by]
+		if !ok {
+			return nil, fmt.Errorf("split: unknown calendar unit %q", by)
+		}
+		result = append(result, tr.SplitByCalendar(unit, loc)...)
+	}
+	return result, nil
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: timerange <union|intersect|subtract|gaps|split|format> [flags] [file...]")
+	fmt.Fprintln(w, "run 'timerange <command> -h' for command flags")
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func runCommand(t *testing.T, stdin string, args ...string) string {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	if err := run(args, strings.NewReader(stdin), &stdout, &stderr); err != nil {
+		t.Fatalf("run(%v) error = %v, stderr = %s", args, err, stderr.String())
+	}
+	return stdout.String()
+}
+
+func TestRun(t *testing.T) {
+	input := "2023-01-01T00:00:00Z/2023-01-01T04:00:00Z\n" +
+		"2023-01-01T02:00:00Z/2023-01-01T06:00:00Z\n"
+
+	t.Run("union", func(t *testing.T) {
+		got := runCommand(t, input, "union")
+		want := "2023-01-01T00:00:00Z/2023-01-01T06:00:00Z\n"
+		if got != want {
+			t.Errorf("union = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("intersect", func(t *testing.T) {
+		got := runCommand(t, input, "intersect")
+		want := "2023-01-01T02:00:00Z/2023-01-01T04:00:00Z\n"
+		if got != want {
+			t.Errorf("intersect = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("gaps", func(t *testing.T) {
+		got := runCommand(t, input, "gaps", "-bounds", "2023-01-01T00:00:00Z/2023-01-01T08:00:00Z")
+		want := "2023-01-01T06:00:00Z/2023-01-01T08:00:00Z\n"
+		if got != want {
+			t.Errorf("gaps = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("subtract", func(t *testing.T) {
+		minus := filepath.Join(t.TempDir(), "minus.txt")
+		if err := os.WriteFile(minus, []byte("2023-01-01T01:00:00Z/2023-01-01T03:00:00Z\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		got := runCommand(t, "2023-01-01T00:00:00Z/2023-01-01T04:00:00Z\n", "subtract", "-minus", minus)
+		want := "2023-01-01T00:00:00Z/2023-01-01T01:00:00Z\n" +
+			"2023-01-01T03:00:00Z/2023-01-01T04:00:00Z\n"
+		if got != want {
+			t.Errorf("subtract = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("split by calendar unit with time zone", func(t *testing.T) {
+		got := runCommand(t, "2023-01-01 12:00/2023-01-02 12:00\n",
+			"split", "-by", "day", "-in-tz", "Europe/Moscow", "-out", "csv")
+		want := "start,end\n" +
+			"2023-01-01T12:00:00+03:00,2023-01-02T00:00:00+03:00\n" +
+			"2023-01-02T00:00:00+03:00,2023-01-02T12:00:00+03:00\n"
+		if got != want {
+			t.Errorf("split = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("format with output zone", func(t *testing.T) {
+		got := runCommand(t, "2023-01-01T00:00:00Z/2023-01-01T01:00:00Z\n", "format", "-tz", "Europe/Moscow")
+		want := "2023-01-01T03:00:00+03:00/2023-01-01T04:00:00+03:00\n"
+		if got != want {
+			t.Errorf("format = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		if err := run([]string{"bogus"}, strings.NewReader(""), &stdout, &stderr); err == nil {
+			t.Error("run() error = nil, want error")
+		}
+	})
+}
@@ -0,0 +1,334 @@
+package timerange
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCron = errors.New("invalid cron expression")
+
+// CronSchedule - разобранное cron-выражение. Поддерживаются 5 полей
+// (минута, час, день месяца, месяц, день недели), 6 полей с секундами
+// в начале, псевдонимы @yearly, @monthly, @weekly, @daily, @hourly,
+// имена месяцев и дней (JAN, SUN) и префикс CRON_TZ=Зона.
+type CronSchedule struct {
+	expr                 string
+	second, minute, hour uint64
+	dom, month, dow      uint64
+	// Если ограничены и день месяца, и день недели, достаточно совпадения
+	// любого из них, как в классическом cron.
+	domAny, dowAny bool
+	loc            *time.Location
+}
+
+type cronField struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronSeconds = cronField{min: 0, max: 59}
+	cronMinutes = cronField{min: 0, max: 59}
+	cronHours   = cronField{min: 0, max: 23}
+	cronDays    = cronField{min: 1, max: 31}
+	cronMonths  = cronField{min: 1, max: 12, names: map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}}
+	// 7 допускается как второе обозначение воскресенья
+	cronWeekdays = cronField{min: 0, max: 7, names: map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}}
+)
+
+var cronAliases = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCron разбирает выражение в зоне loc (nil - UTC).
+// Префикс CRON_TZ= или TZ= в выражении имеет приоритет над loc.
+func ParseCron(expr string, loc *time.Location) (*CronSchedule, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	spec := strings.TrimSpace(expr)
+	if strings.HasPrefix(spec, "CRON_TZ=") || strings.HasPrefix(spec, "TZ=") {
+		zone, rest, _ := strings.Cut(spec, " ")
+		_, name, _ := strings.Cut(zone, "=")
+		var err error
+		if loc, err = time.LoadLocation(name); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCron, err)
+		}
+		spec = strings.TrimSpace(rest)
+	}
+	if alias, ok := cronAliases[strings.ToLower(spec)]; ok {
+		spec = alias
+	}
+
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("%w: %q: expected 5 or 6 fields", ErrInvalidCron, expr)
+	}
+
+	s := &CronSchedule{expr: expr, loc: loc}
+	targets := []struct {
+		bits  *uint64
+		field cronField
+	}{
+		{&s.second, cronSeconds},
+		{&s.minute, cronMinutes},
+		{&s.hour, cronHours},
+		{&s.dom, cronDays},
+		{&s.month, cronMonths},
+		{&s.dow, cronWeekdays},
+	}
+	for i, target := range targets {
+		set, err := parseCronField(fields[i], target.field)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", ErrInvalidCron, expr, err)
+		}
+		*target.bits = set
+	}
+
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domAny = unrestricted(fields[3])
+	s.dowAny = unrestricted(fields[5])
+	return s, nil
+}
+
+func (s *CronSchedule) Location() *time.Location {
+	return s.loc
+}
+
+func (s *CronSchedule) String() string {
+	return s.expr
+}
+
+// Next возвращает первое срабатывание строго после t или нулевое время,
+// если в ближайшие пять лет срабатываний нет. Моменты, пропущенные при
+// переходе на летнее время, не срабатывают.
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	t = t.In(s.loc).Truncate(time.Second).Add(time.Second)
+	yearLimit := t.Year() + 5
+
+search:
+	for t.Year() <= yearLimit {
+		for !hasBit(s.month, int(t.Month())) {
+			t = s.midnight(t.Year(), t.Month()+1, 1)
+			if t.Year() > yearLimit {
+				return time.Time{}
+			}
+		}
+		for !s.dayMatches(t) {
+			t = s.midnight(t.Year(), t.Month(), t.Day()+1)
+			if t.Day() == 1 {
+				continue search
+			}
+		}
+		// Часы и минуты перебираем по длительности: time.Date возвращает
+		// первое вхождение повторяющегося часа и может отступить назад из
+		// пропущенного при переходе на летнее время
+		for !hasBit(s.hour, t.Hour()) {
+			next := t.Add(time.Hour - time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second)
+			if next.Day() != t.Day() {
+				t = next
+				continue search
+			}
+			t = next
+		}
+		for !hasBit(s.minute, t.Minute()) {
+			t = t.Add(time.Duration(60-t.Second()) * time.Second)
+			if t.Minute() == 0 {
+				continue search
+			}
+		}
+		for !hasBit(s.second, t.Second()) {
+			t = t.Add(time.Second)
+			if t.Second() == 0 {
+				continue search
+			}
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// Times возвращает все срабатывания в пределах [bounds.Start, bounds.End).
+func (s *CronSchedule) Times(bounds TimeRange) []time.Time {
+	var result []time.Time
+	for t := s.Next(bounds.Start.Add(-time.Nanosecond)); !t.IsZero() && t.Before(bounds.End); t = s.Next(t) {
+		result = append(result, t)
+	}
+	return result
+}
+
+// --- Cron Windows ---
+
+// CronWindow - повторяющееся окно: начало по расписанию и фиксированная
+// длительность, например "0 2 * * SUN" на 3 часа.
+type CronWindow struct {
+	Schedule *CronSchedule
+	Duration time.Duration
+}
+
+func NewCronWindow(expr string, d time.Duration, loc *time.Location) (CronWindow, error) {
+	if d <= 0 {
+		return CronWindow{}, ErrInvalidArgument
+	}
+	schedule, err := ParseCron(expr, loc)
+	if err != nil {
+		return CronWindow{}, err
+	}
+	return CronWindow{Schedule: schedule, Duration: d}, nil
+}
+
+// Windows возвращает окна, пересекающие bounds, обрезанные по его границам.
+// Пересекающиеся окна объединяются, поэтому результат можно передать в FindGaps.
+func (w CronWindow) Windows(bounds TimeRange) []TimeRange {
+	var windows []TimeRange
+	for start := w.Schedule.Next(bounds.Start.Add(-w.Duration)); !start.IsZero() && start.Before(bounds.End); start = w.Schedule.Next(start) {
+		windows = append(windows, TimeRange{
+			Start: maxTime(start, bounds.Start),
+			End:   minTime(start.Add(w.Duration), bounds.End),
+		})
+	}
+	merged, _ := MergeOverlapping(windows)
+	return merged
+}
+
+// Ranges реализует Source, поэтому окна cron можно использовать в выражениях и BlackoutPolicy.
+func (w CronWindow) Ranges(bounds TimeRange) []TimeRange {
+	return w.Windows(bounds)
+}
+
+// IsActive сообщает, попадает ли t в какое-либо окно. Окна полуоткрытые.
+func (w CronWindow) IsActive(t time.Time) bool {
+	start := w.Schedule.Next(t.Add(-w.Duration))
+	return !start.IsZero() && !start.After(t)
+}
+
+// Next возвращает первое окно, начинающееся строго после t.
+func (w CronWindow) Next(t time.Time) (TimeRange, bool) {
+	start := w.Schedule.Next(t)
+	if start.IsZero() {
+		return TimeRange{}, false
+	}
+	return TimeRange{Start: start, End: start.Add(w.Duration)}, true
+}
+
+// --- Helper Functions ---
+
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := hasBit(s.dom, t.Day())
+	dowMatch := hasBit(s.dow, int(t.Weekday()))
+	if s.domAny || s.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// midnight возвращает начало дня в зоне расписания. Если полночь пропущена
+// при переходе на летнее время, возвращается первый момент после пропуска.
+func (s *CronSchedule) midnight(year int, month time.Month, day int) time.Time {
+	t := time.Date(year, month, day, 0, 0, 0, 0, s.loc)
+	want := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	if wall := wallClock(t); wall.Before(want) {
+		t = t.Add(want.Sub(wall))
+	}
+	return t
+}
+
+// wallClock возвращает показания часов t как момент в UTC.
+func wallClock(t time.Time) time.Time {
+	year, month, day := t.Date()
+	hour, minute, sec := t.Clock()
+	return time.Date(year, month, day, hour, minute, sec, t.Nanosecond(), time.UTC)
+}
+
+func hasBit(set uint64, v int) bool {
+	return set&(1<<uint(v)) != 0
+}
+
+func isWildcard(field string) bool {
+	return field == "*" || field == "?"
+}
+
+// unrestricted сообщает, что поле дня начинается со звездочки, как */2.
+func unrestricted(field string) bool {
+	return strings.HasPrefix(field, "*") || field == "?"
+}
+
+// parseCronField разбирает список элементов вида *, a, a-b, */n, a-b/n и a/n.
+func parseCronField(field string, f cronField) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", part)
+			}
+		}
+
+		var lo, hi int
+		switch {
+		case isWildcard(rangePart):
+			lo, hi = f.min, f.max
+		case strings.Contains(rangePart, "-"):
+			from, to, _ := strings.Cut(rangePart, "-")
+			var err1, err2 error
+			lo, err1 = f.value(from)
+			hi, err2 = f.value(to)
+			if err1 != nil || err2 != nil || lo > hi {
+				return 0, fmt.Errorf("invalid range %q", part)
+			}
+		default:
+			v, err := f.value(rangePart)
+			if err != nil {
+				return 0, err
+			}
+			lo, hi = v, v
+			if hasStep {
+				hi = f.max
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	if bits.OnesCount64(set) == 0 {
+		return 0, fmt.Errorf("empty field %q", field)
+	}
+	return set, nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToUpper(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %q out of range %d-%d", s, f.min, f.max)
+	}
+	return v, nil
+}
@@ -0,0 +1,270 @@
+package timerange
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	valid := []string{
+		"0 2 * * SUN",
+		"*/15 9-17 * * MON-FRI",
+		"30 0 2 * * *",
+		"0 0 1,15 * *",
+		"0 12 * JAN-MAR 7",
+		"@daily",
+		"@HOURLY",
+		"CRON_TZ=UTC 0 2 * * *",
+	}
+	for _, expr := range valid {
+		if _, err := ParseCron(expr, nil); err != nil {
+			t.Errorf("ParseCron(%q) error = %v", expr, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"0 24 * * *",
+		"0 0 0 * *",
+		"0 0 * 13 *",
+		"0 0 * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"0 0 * * FOO",
+		"@fortnightly",
+		"CRON_TZ=Nowhere/City 0 0 * * *",
+	}
+	for _, expr := range invalid {
+		if _, err := ParseCron(expr, nil); !errors.Is(err, ErrInvalidCron) {
+			t.Errorf("ParseCron(%q) error = %v, want ErrInvalidCron", expr, err)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	// 2024-05-01 - среда
+	from := time.Date(2024, 5, 1, 10, 20, 30, 0, time.UTC)
+
+	tests := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"0 2 * * SUN", time.Date(2024, 5, 5, 2, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)},
+		{"45 * * * * *", time.Date(2024, 5, 1, 10, 20, 45, 0, time.UTC)},
+		{"@daily", time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 9 * * 7", time.Date(2024, 5, 5, 9, 0, 0, 0, time.UTC)},
+		// День месяца или день недели: 13-е число или ближайшая пятница
+		{"0 0 13 * FRI", time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC)},
+		// Звездочка с шагом не ограничивает день месяца
+		{"0 0 */2 * MON", time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			schedule, err := ParseCron(tt.expr, nil)
+			if err != nil {
+				t.Fatalf("ParseCron() error = %v", err)
+			}
+			if got := schedule.Next(from); !got.Equal(tt.expected) {
+				t.Errorf("Next() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	t.Run("strictly after", func(t *testing.T) {
+		schedule, _ := ParseCron("@hourly", nil)
+		at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		if got := schedule.Next(at); !got.Equal(at.Add(time.Hour)) {
+			t.Errorf("Next() = %v, want %v", got, at.Add(time.Hour))
+		}
+	})
+
+	t.Run("impossible date", func(t *testing.T) {
+		schedule, _ := ParseCron("0 0 31 2 *", nil)
+		if got := schedule.Next(from); !got.IsZero() {
+			t.Errorf("Next() = %v, want zero time", got)
+		}
+	})
+}
+
+func TestCronTimeZones(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+	from := time.Date(2024, 3, 30, 12, 0, 0, 0, time.UTC)
+
+	t.Run("loc parameter", func(t *testing.T) {
+		schedule, _ := ParseCron("0 9 * * *", berlin)
+		expected := time.Date(2024, 3, 31, 9, 0, 0, 0, berlin)
+		if got := schedule.Next(from); !got.Equal(expected) {
+			t.Errorf("Next() = %v, want %v", got, expected)
+		}
+	})
+
+	t.Run("CRON_TZ prefix overrides loc", func(t *testing.T) {
+		schedule, _ := ParseCron("CRON_TZ=Europe/Berlin 0 9 * * *", time.UTC)
+		if schedule.Location().String() != "Europe/Berlin" {
+			t.Errorf("Location() = %v, want Europe/Berlin", schedule.Location())
+		}
+	})
+
+	t.Run("time skipped by DST does not fire", func(t *testing.T) {
+		schedule, _ := ParseCron("30 2 * * *", berlin)
+		expected := time.Date(2024, 4, 1, 2, 30, 0, 0, berlin)
+		if got := schedule.Next(from); !got.Equal(expected) {
+			t.Errorf("Next() = %v, want %v", got, expected)
+		}
+	})
+
+	t.Run("spring forward in New York", func(t *testing.T) {
+		newYork, err := time.LoadLocation("America/New_York")
+		if err != nil {
+			t.Skip("tzdata not available")
+		}
+		// 2024-03-10 02:00-03:00 не существует
+		schedule, _ := ParseCron("0 2 * * SUN", newYork)
+		expected := time.Date(2024, 3, 17, 2, 0, 0, 0, newYork)
+		if got := schedule.Next(time.Date(2024, 3, 9, 12, 0, 0, 0, newYork)); !got.Equal(expected) {
+			t.Errorf("Next() = %v, want %v", got, expected)
+		}
+
+		window, _ := NewCronWindow("0 2 * * SUN", 3*time.Hour, newYork)
+		year := TimeRange{Start: time.Date(2024, 1, 1, 0, 0, 0, 0, newYork), End: time.Date(2025, 1, 1, 0, 0, 0, 0, newYork)}
+		if windows := window.Windows(year); len(windows) != 51 {
+			t.Errorf("Windows() returned %d windows, want 51", len(windows))
+		}
+	})
+
+	t.Run("midnight skipped in Santiago", func(t *testing.T) {
+		santiago, err := time.LoadLocation("America/Santiago")
+		if err != nil {
+			t.Skip("tzdata not available")
+		}
+		// 2024-09-08 часы переводятся с 00:00 на 01:00
+		schedule, _ := ParseCron("0 12 * * *", santiago)
+		expected := time.Date(2024, 9, 8, 12, 0, 0, 0, santiago)
+		if got := schedule.Next(time.Date(2024, 9, 7, 13, 0, 0, 0, santiago)); !got.Equal(expected) {
+			t.Errorf("Next() = %v, want %v", got, expected)
+		}
+
+		// Пропущенная полночь не срабатывает
+		daily, _ := ParseCron("@daily", santiago)
+		expected = time.Date(2024, 9, 9, 0, 0, 0, 0, santiago)
+		if got := daily.Next(time.Date(2024, 9, 7, 13, 0, 0, 0, santiago)); !got.Equal(expected) {
+			t.Errorf("Next() = %v, want %v", got, expected)
+		}
+	})
+
+	t.Run("hour repeated by DST fires after t", func(t *testing.T) {
+		newYork, err := time.LoadLocation("America/New_York")
+		if err != nil {
+			t.Skip("tzdata not available")
+		}
+		// 2024-11-03 01:30 EST - второе вхождение часа 01:00
+		second := time.Date(2024, 11, 3, 6, 30, 0, 0, time.UTC)
+		schedule, _ := ParseCron("45 * * * *", newYork)
+
+		tests := []struct {
+			from     time.Time
+			expected time.Time
+		}{
+			{second.Add(-time.Hour), second.Add(-45 * time.Minute)},
+			{second.Add(-40 * time.Minute), second.Add(15 * time.Minute)},
+			{second.Add(-30 * time.Minute), second.Add(15 * time.Minute)},
+			{second, second.Add(15 * time.Minute)},
+		}
+		for _, tt := range tests {
+			if got := schedule.Next(tt.from); !got.Equal(tt.expected) {
+				t.Errorf("Next(%v) = %v, want %v", tt.from.In(newYork), got, tt.expected.In(newYork))
+			}
+		}
+
+		window := CronWindow{Schedule: schedule, Duration: 5 * time.Minute}
+		if window.IsActive(second) {
+			t.Errorf("IsActive(%v) = true, want false", second.In(newYork))
+		}
+	})
+}
+
+func TestCronWindow(t *testing.T) {
+	// Обслуживание каждое воскресенье в 02:00 на 3 часа
+	window, err := NewCronWindow("0 2 * * SUN", 3*time.Hour, time.UTC)
+	if err != nil {
+		t.Fatalf("NewCronWindow() error = %v", err)
+	}
+	sunday := time.Date(2024, 5, 5, 0, 0, 0, 0, time.UTC)
+
+	t.Run("windows are clipped to bounds", func(t *testing.T) {
+		bounds := TimeRange{Start: sunday.Add(3 * time.Hour), End: sunday.AddDate(0, 0, 8)}
+		expected := []TimeRange{
+			{Start: sunday.Add(3 * time.Hour), End: sunday.Add(5 * time.Hour)},
+			{Start: sunday.AddDate(0, 0, 7).Add(2 * time.Hour), End: sunday.AddDate(0, 0, 7).Add(5 * time.Hour)},
+		}
+		if result := window.Windows(bounds); !compareRanges(result, expected) {
+			t.Errorf("Windows() = %v, want %v", result, expected)
+		}
+	})
+
+	t.Run("IsActive", func(t *testing.T) {
+		tests := []struct {
+			at       time.Time
+			expected bool
+		}{
+			{sunday.Add(time.Hour), false},
+			{sunday.Add(2 * time.Hour), true},
+			{sunday.Add(4*time.Hour + 59*time.Minute), true},
+			{sunday.Add(5 * time.Hour), false},
+		}
+		for _, tt := range tests {
+			if got := window.IsActive(tt.at); got != tt.expected {
+				t.Errorf("IsActive(%v) = %v, want %v", tt.at, got, tt.expected)
+			}
+		}
+	})
+
+	t.Run("Next", func(t *testing.T) {
+		next, ok := window.Next(sunday.Add(3 * time.Hour))
+		expected := TimeRange{Start: sunday.AddDate(0, 0, 7).Add(2 * time.Hour), End: sunday.AddDate(0, 0, 7).Add(5 * time.Hour)}
+		if !ok || !next.Equal(expected) {
+			t.Errorf("Next() = %v, %v, want %v", next, ok, expected)
+		}
+	})
+
+	t.Run("overlapping windows are merged", func(t *testing.T) {
+		hourly, _ := NewCronWindow("@hourly", 90*time.Minute, time.UTC)
+		bounds := TimeRange{Start: sunday, End: sunday.Add(3 * time.Hour)}
+		expected := []TimeRange{bounds}
+		if result := hourly.Windows(bounds); !compareRanges(result, expected) {
+			t.Errorf("Windows() = %v, want %v", result, expected)
+		}
+	})
+
+	t.Run("composes with FindGaps", func(t *testing.T) {
+		bounds := TimeRange{Start: sunday, End: sunday.AddDate(0, 0, 1)}
+		gaps, err := FindGaps(window.Windows(bounds), bounds)
+		if err != nil {
+			t.Fatalf("FindGaps() error = %v", err)
+		}
+		expected := []TimeRange{
+			{Start: sunday, End: sunday.Add(2 * time.Hour)},
+			{Start: sunday.Add(5 * time.Hour), End: sunday.AddDate(0, 0, 1)},
+		}
+		if !compareRanges(gaps, expected) {
+			t.Errorf("FindGaps() = %v, want %v", gaps, expected)
+		}
+	})
+
+	t.Run("invalid duration", func(t *testing.T) {
+		if _, err := NewCronWindow("@daily", 0, nil); err != ErrInvalidArgument {
+			t.Errorf("NewCronWindow() error = %v, want ErrInvalidArgument", err)
+		}
+	})
+}
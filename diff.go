@@ -0,0 +1,141 @@
+package timerange
+
+// --- Range Set Diff ---
+
+// Diff сравнивает два множества интервалов и возвращает время, которое
+// появилось в new (added) и исчезло из old (removed). Входные интервалы могут
+// пересекаться - оба множества предварительно объединяются MergeOverlapping.
+func Diff(old, new []TimeRange) (added, removed []TimeRange) {
+	mergedOld, _ := MergeOverlapping(old)
+	mergedNew, _ := MergeOverlapping(new)
+
+	for _, tr := range mergedNew {
+		added = append(added, tr.SubtractAll(mergedOld)...)
+	}
+	for _, tr := range mergedOld {
+		removed = append(removed, tr.SubtractAll(mergedNew)...)
+	}
+	return added, removed
+}
+
+// Patch - переносимое представление изменений множества интервалов.
+type Patch struct {
+	Added   []TimeRange `json:"added,omitempty"`
+	Removed []TimeRange `json:"removed,omitempty"`
+}
+
+func NewPatch(old, new []TimeRange) Patch {
+	added, removed := Diff(old, new)
+	return Patch{Added: added, Removed: removed}
+}
+
+func (p Patch) IsEmpty() bool {
+	return len(p.Added) == 0 && len(p.Removed) == 0
+}
+
+// Apply применяет патч к ranges: сначала вычитает Removed, затем добавляет Added.
+// Результат объединен и отсортирован, поэтому NewPatch(old, new).Apply(old)
+// дает объединенное множество new.
+func (p Patch) Apply(ranges []TimeRange) []TimeRange {
+	merged, _ := MergeOverlapping(ranges)
+
+	var kept []TimeRange
+	for _, tr := range merged {
+		kept = append(kept, tr.SubtractAll(p.Removed)...)
+	}
+	result, _ := MergeOverlapping(append(kept, p.Added...))
+	return dropEmpty(result)
+}
+
+// --- Interval Diff ---
+
+// ValueChange - интервал, на котором значение изменилось с Old на New.
+type ValueChange[V any] struct {
+	Range TimeRange `json:"range"`
+	Old   V         `json:"old"`
+	New   V         `json:"new"`
+}
+
+// IntervalDiff описывает изменения ступенчатой функции: интервалы, где значение
+// появилось, исчезло или поменялось. Соседние отрезки с одинаковыми значениями
+// объединяются.
+type IntervalDiff[V comparable] struct {
+	Added   []Interval[V]    `json:"added,omitempty"`
+	Removed []Interval[V]    `json:"removed,omitempty"`
+	Changed []ValueChange[V] `json:"changed,omitempty"`
+}
+
+// DiffIntervals сравнивает два набора интервалов со значениями. Пересечения
+// внутри набора разрешаются как в RangeMap: более поздний интервал перекрывает
+// более ранний.
+func DiffIntervals[V comparable](old, new []Interval[V]) IntervalDiff[V] {
+	oldMap, newMap := NewRangeMap(old...), NewRangeMap(new...)
+
+	points := boundaries(append(oldMap.Intervals(), newMap.Intervals()...))
+
+	var diff IntervalDiff[V]
+	for i := 0; i+1 < len(points); i++ {
+		segment := TimeRange{Start: points[i], End: points[i+1]}
+		oldValue, hadOld := oldMap.Get(segment.Start)
+		newValue, hasNew := newMap.Get(segment.Start)
+
+		switch {
+		case hadOld && hasNew && oldValue != newValue:
+			diff.Changed = appendChange(diff.Changed, ValueChange[V]{Range: segment, Old: oldValue, New: newValue})
+		case hadOld && !hasNew:
+			diff.Removed = appendInterval(diff.Removed, Interval[V]{TimeRange: segment, Value: oldValue})
+		case !hadOld && hasNew:
+			diff.Added = appendInterval(diff.Added, Interval[V]{TimeRange: segment, Value: newValue})
+		}
+	}
+	return diff
+}
+
+func (d IntervalDiff[V]) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Apply переносит изменения в m: удаляет Removed и записывает новые значения
+// из Added и Changed.
+func (d IntervalDiff[V]) Apply(m *RangeMap[V]) {
+	for _, iv := range d.Removed {
+		m.Delete(iv.TimeRange)
+	}
+	for _, iv := range d.Added {
+		m.Set(iv.TimeRange, iv.Value)
+	}
+	for _, change := range d.Changed {
+		m.Set(change.Range, change.New)
+	}
+}
+
+// --- Helper Functions ---
+
+func appendInterval[V comparable](intervals []Interval[V], iv Interval[V]) []Interval[V] {
+	if n := len(intervals); n > 0 && intervals[n-1].End.Equal(iv.Start) && intervals[n-1].Value == iv.Value {
+		intervals[n-1].End = iv.End
+		return intervals
+	}
+	return append(intervals, iv)
+}
+
+func appendChange[V comparable](changes []ValueChange[V], change ValueChange[V]) []ValueChange[V] {
+	if n := len(changes); n > 0 {
+		last := &changes[n-1]
+		if last.Range.End.Equal(change.Range.Start) && last.Old == change.Old && last.New == change.New {
+			last.Range.End = change.Range.End
+			return changes
+		}
+	}
+	return append(changes, change)
+}
+
+func dropEmpty(ranges []TimeRange) []TimeRange {
+	var result []TimeRange
+	for _, tr := range ranges {
+		if tr.Start.Before(tr.End) {
+			result = append(result, tr)
+		}
+	}
+	return result
+}
@@ -0,0 +1,145 @@
+package timerange
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name            string
+		old             []TimeRange
+		new             []TimeRange
+		expectedAdded   []TimeRange
+		expectedRemoved []TimeRange
+	}{
+		{
+			name:          "extended range",
+			old:           []TimeRange{hourRange(9, 12)},
+			new:           []TimeRange{hourRange(9, 14)},
+			expectedAdded: []TimeRange{hourRange(12, 14)},
+		},
+		{
+			name:            "shifted range",
+			old:             []TimeRange{hourRange(9, 12)},
+			new:             []TimeRange{hourRange(10, 13)},
+			expectedAdded:   []TimeRange{hourRange(12, 13)},
+			expectedRemoved: []TimeRange{hourRange(9, 10)},
+		},
+		{
+			name:            "hole punched in range",
+			old:             []TimeRange{hourRange(9, 17)},
+			new:             []TimeRange{hourRange(9, 12), hourRange(13, 17)},
+			expectedRemoved: []TimeRange{hourRange(12, 13)},
+		},
+		{
+			name: "overlapping input is merged first",
+			old:  []TimeRange{hourRange(9, 11), hourRange(10, 12)},
+			new:  []TimeRange{hourRange(9, 12)},
+		},
+		{
+			name:          "from empty",
+			new:           []TimeRange{hourRange(1, 2), hourRange(3, 4)},
+			expectedAdded: []TimeRange{hourRange(1, 2), hourRange(3, 4)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, removed := Diff(tt.old, tt.new)
+			if !compareRanges(added, tt.expectedAdded) {
+				t.Errorf("Diff() added = %v, want %v", added, tt.expectedAdded)
+			}
+			if !compareRanges(removed, tt.expectedRemoved) {
+				t.Errorf("Diff() removed = %v, want %v", removed, tt.expectedRemoved)
+			}
+		})
+	}
+}
+
+func TestPatchApply(t *testing.T) {
+	old := []TimeRange{hourRange(0, 4), hourRange(6, 10)}
+	new := []TimeRange{hourRange(2, 7), hourRange(12, 14)}
+
+	patch := NewPatch(old, new)
+	expected, _ := MergeOverlapping(new)
+	if result := patch.Apply(old); !compareRanges(result, expected) {
+		t.Errorf("Apply() = %v, want %v", result, expected)
+	}
+
+	data, err := json.Marshal(patch)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var decoded Patch
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if result := decoded.Apply(old); !compareRanges(result, expected) {
+		t.Errorf("Apply() after JSON round trip = %v, want %v", result, expected)
+	}
+
+	if !NewPatch(old, old).IsEmpty() {
+		t.Error("NewPatch() for equal sets should be empty")
+	}
+}
+
+func TestDiffIntervals(t *testing.T) {
+	old := []Interval[string]{
+		{TimeRange: hourRange(0, 4), Value: "alice"},
+		{TimeRange: hourRange(4, 8), Value: "bob"},
+	}
+	new := []Interval[string]{
+		{TimeRange: hourRange(0, 2), Value: "alice"},
+		{TimeRange: hourRange(2, 6), Value: "carol"},
+		{TimeRange: hourRange(6, 8), Value: "bob"},
+		{TimeRange: hourRange(8, 10), Value: "bob"},
+	}
+
+	diff := DiffIntervals(old, new)
+
+	expectedAdded := []Interval[string]{{TimeRange: hourRange(8, 10), Value: "bob"}}
+	if !equalIntervals(diff.Added, expectedAdded) {
+		t.Errorf("DiffIntervals() added = %v, want %v", diff.Added, expectedAdded)
+	}
+	if len(diff.Removed) != 0 {
+		t.Errorf("DiffIntervals() removed = %v, want none", diff.Removed)
+	}
+
+	expectedChanged := []ValueChange[string]{
+		{Range: hourRange(2, 4), Old: "alice", New: "carol"},
+		{Range: hourRange(4, 6), Old: "bob", New: "carol"},
+	}
+	if len(diff.Changed) != len(expectedChanged) {
+		t.Fatalf("DiffIntervals() changed = %v, want %v", diff.Changed, expectedChanged)
+	}
+	for i, change := range diff.Changed {
+		e := expectedChanged[i]
+		if !change.Range.Equal(e.Range) || change.Old != e.Old || change.New != e.New {
+			t.Errorf("DiffIntervals() changed[%d] = %v, want %v", i, change, e)
+		}
+	}
+
+	t.Run("apply reproduces new", func(t *testing.T) {
+		m := NewRangeMap(old...)
+		diff.Apply(m)
+		expected := NewRangeMap(new...).Intervals()
+		if result := m.Intervals(); !equalIntervals(result, expected) {
+			t.Errorf("Apply() = %v, want %v", result, expected)
+		}
+	})
+
+	t.Run("removed values", func(t *testing.T) {
+		diff := DiffIntervals(old, old[:1])
+		expected := []Interval[string]{{TimeRange: hourRange(4, 8), Value: "bob"}}
+		if !equalIntervals(diff.Removed, expected) {
+			t.Errorf("DiffIntervals() removed = %v, want %v", diff.Removed, expected)
+		}
+	})
+
+	t.Run("equal sets", func(t *testing.T) {
+		if diff := DiffIntervals(old, old); !diff.IsEmpty() {
+			t.Errorf("DiffIntervals() = %+v, want empty", diff)
+		}
+	})
+}
@@ -0,0 +1,420 @@
+package timerange
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidExpr = errors.New("invalid time window expression")
+
+// Source - любой источник интервалов, который можно развернуть в пределах bounds.
+type Source interface {
+	Ranges(bounds TimeRange) []TimeRange
+}
+
+// SourceFunc позволяет использовать функцию как Source.
+type SourceFunc func(bounds TimeRange) []TimeRange
+
+func (f SourceFunc) Ranges(bounds TimeRange) []TimeRange {
+	return f(bounds)
+}
+
+// ExprOptions настраивает CompileExpr.
+type ExprOptions struct {
+	// Location - зона для дней недели, окон времени суток и моментов без
+	// смещения, если в выражении зона не указана. По умолчанию UTC.
+	Location *time.Location
+	// Calendars - именованные наборы интервалов, например "holidays(ru)".
+	Calendars map[string]Source
+}
+
+// Expr - скомпилированное выражение над наборами интервалов.
+//
+// Операторы по убыванию приоритета: ! (дополнение в пределах bounds),
+// & (пересечение), | (объединение); разность записывается как a & !b.
+// Термы:
+//   - weekdays, weekends, дни недели (mon, friday) и их диапазоны (mon-fri);
+//   - окно времени суток HH:MM-HH:MM, в том числе через полночь (22:00-06:00);
+//   - интервал ISO 8601: 2024-12-31T10:00/PT2H;
+//   - имя календаря из ExprOptions.Calendars.
+//
+// После дней недели и окон времени суток можно указать зону:
+// "09:00-18:00 Europe/Moscow".
+type Expr struct {
+	source string
+	root   exprNode
+}
+
+// CompileExpr разбирает выражение один раз; результат можно вычислять для
+// любых bounds и использовать конкурентно.
+func CompileExpr(source string, opts ExprOptions) (*Expr, error) {
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	tokens, err := tokenizeExpr(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &exprParser{tokens: tokens, opts: opts}
+	root, err := p.parseUnion()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("%w: unexpected %q", ErrInvalidExpr, p.tokens[p.pos])
+	}
+	return &Expr{source: source, root: root}, nil
+}
+
+// Evaluate возвращает отсортированные непересекающиеся интервалы в пределах bounds.
+func (e *Expr) Evaluate(bounds TimeRange) []TimeRange {
+	if !bounds.Start.Before(bounds.End) {
+		return nil
+	}
+	return e.root.eval(bounds)
+}
+
+// Ranges реализует Source, поэтому выражение можно подключить как календарь.
+func (e *Expr) Ranges(bounds TimeRange) []TimeRange {
+	return e.Evaluate(bounds)
+}
+
+func (e *Expr) String() string {
+	return e.source
+}
+
+// --- Syntax Tree ---
+
+// exprNode вычисляет отсортированный объединенный набор в пределах bounds.
+type exprNode interface {
+	eval(bounds TimeRange) []TimeRange
+}
+
+type unionNode struct{ left, right exprNode }
+
+func (n unionNode) eval(bounds TimeRange) []TimeRange {
+	merged, _ := MergeOverlapping(append(n.left.eval(bounds), n.right.eval(bounds)...))
+	return dropEmpty(merged)
+}
+
+type intersectNode struct{ left, right exprNode }
+
+func (n intersectNode) eval(bounds TimeRange) []TimeRange {
+	return intersectSets(n.left.eval(bounds), n.right.eval(bounds))
+}
+
+type notNode struct{ operand exprNode }
+
+func (n notNode) eval(bounds TimeRange) []TimeRange {
+	return bounds.SubtractAll(n.operand.eval(bounds))
+}
+
+type weekdayNode struct {
+	days uint8
+	loc  *time.Location
+}
+
+func (n weekdayNode) eval(bounds TimeRange) []TimeRange {
+	var ranges []TimeRange
+	for day := startOfUnit(bounds.Start, UnitDay, n.loc, time.Monday); day.Before(bounds.End); day = addUnits(day, UnitDay, 1) {
+		if n.days&(1<<uint(day.Weekday())) != 0 {
+			ranges = append(ranges, TimeRange{Start: day, End: addUnits(day, UnitDay, 1)})
+		}
+	}
+	return clipSet(ranges, bounds)
+}
+
+// dailyNode - окно времени суток; from и to в минутах от полуночи.
+type dailyNode struct {
+	from, to int
+	loc      *time.Location
+}
+
+func (n dailyNode) eval(bounds TimeRange) []TimeRange {
+	var ranges []TimeRange
+	first := addUnits(startOfUnit(bounds.Start, UnitDay, n.loc, time.Monday), UnitDay, -1)
+	for day := first; day.Before(bounds.End); day = addUnits(day, UnitDay, 1) {
+		y, m, d := day.Date()
+		start := time.Date(y, m, d, 0, n.from, 0, 0, n.loc)
+		end := time.Date(y, m, d, 0, n.to, 0, 0, n.loc)
+		if n.to <= n.from {
+			end = time.Date(y, m, d+1, 0, n.to, 0, 0, n.loc)
+		}
+		ranges = append(ranges, TimeRange{Start: start, End: end})
+	}
+	return clipSet(ranges, bounds)
+}
+
+type fixedNode struct{ tr TimeRange }
+
+func (n fixedNode) eval(bounds TimeRange) []TimeRange {
+	return clipSet([]TimeRange{n.tr}, bounds)
+}
+
+type calendarNode struct{ source Source }
+
+func (n calendarNode) eval(bounds TimeRange) []TimeRange {
+	return clipSet(n.source.Ranges(bounds), bounds)
+}
+
+// --- Parser ---
+
+type exprParser struct {
+	tokens []string
+	pos    int
+	opts   ExprOptions
+}
+
+func (p *exprParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *exprParser) parseUnion() (exprNode, error) {
+	left, err := p.parseIntersection()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "|" {
+		p.pos++
+		right, err := p.parseIntersection()
+		if err != nil {
+			return nil, err
+		}
+		left = unionNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseIntersection() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "&" {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = intersectNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	switch p.peek() {
+	case "!":
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand}, nil
+	case "(":
+		p.pos++
+		inner, err := p.parseUnion()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("%w: missing )", ErrInvalidExpr)
+		}
+		p.pos++
+		return inner, nil
+	case "", "|", "&", ")":
+		return nil, fmt.Errorf("%w: expected term, got %q", ErrInvalidExpr, p.peek())
+	}
+
+	term := p.tokens[p.pos]
+	p.pos++
+	return p.parseTerm(term)
+}
+
+func (p *exprParser) parseTerm(term string) (exprNode, error) {
+	if source, ok := p.opts.Calendars[term]; ok {
+		return calendarNode{source}, nil
+	}
+	if days, ok := parseWeekdays(term); ok {
+		loc, err := p.parseZone()
+		if err != nil {
+			return nil, err
+		}
+		return weekdayNode{days: days, loc: loc}, nil
+	}
+	if from, to, ok := parseDailyWindow(term); ok {
+		loc, err := p.parseZone()
+		if err != nil {
+			return nil, err
+		}
+		return dailyNode{from: from, to: to, loc: loc}, nil
+	}
+	if strings.Contains(term, "/") {
+		tr, err := ParseISOInterval(term, p.opts.Location)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidExpr, err)
+		}
+		return fixedNode{tr}, nil
+	}
+	return nil, fmt.Errorf("%w: unknown term %q", ErrInvalidExpr, term)
+}
+
+// parseZone читает необязательную зону после терма.
+func (p *exprParser) parseZone() (*time.Location, error) {
+	switch p.peek() {
+	case "", "|", "&", "!", "(", ")":
+		return p.opts.Location, nil
+	}
+	name := p.tokens[p.pos]
+	p.pos++
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown zone %q", ErrInvalidExpr, name)
+	}
+	return loc, nil
+}
+
+// tokenizeExpr делит выражение на операторы и термы. Скобки сразу после
+// слова, как в holidays(ru), считаются частью терма.
+func tokenizeExpr(source string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(source); {
+		switch c := source[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.IndexByte("|&!()", c) >= 0:
+			tokens = append(tokens, string(c))
+			i++
+		default:
+			start := i
+			for i < len(source) && strings.IndexByte(" \t\n\r|&!()", source[i]) < 0 {
+				i++
+			}
+			if i < len(source) && source[i] == '(' {
+				end := strings.IndexByte(source[i:], ')')
+				if end < 0 {
+					return nil, fmt.Errorf("%w: missing ) in %q", ErrInvalidExpr, source[start:])
+				}
+				i += end + 1
+			}
+			tokens = append(tokens, source[start:i])
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("%w: empty expression", ErrInvalidExpr)
+	}
+	return tokens, nil
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// parseWeekdays возвращает маску дней недели (бит i - time.Weekday(i)).
+func parseWeekdays(term string) (uint8, bool) {
+	term = strings.ToLower(term)
+	switch term {
+	case "weekdays":
+		return 0b0111110, true
+	case "weekends":
+		return 0b1000001, true
+	}
+
+	fromName, toName, isRange := strings.Cut(term, "-")
+	from, ok := weekdayNames[fromName]
+	if !ok {
+		return 0, false
+	}
+	if !isRange {
+		return 1 << uint(from), true
+	}
+	to, ok := weekdayNames[toName]
+	if !ok {
+		return 0, false
+	}
+
+	var days uint8
+	for d := from; ; d = (d + 1) % 7 {
+		days |= 1 << uint(d)
+		if d == to {
+			return days, true
+		}
+	}
+}
+
+// parseDailyWindow разбирает HH:MM-HH:MM; конец 24:00 допускается.
+func parseDailyWindow(term string) (int, int, bool) {
+	fromText, toText, ok := strings.Cut(term, "-")
+	if !ok {
+		return 0, 0, false
+	}
+	from, ok1 := parseClock(fromText)
+	to, ok2 := parseClock(toText)
+	if !ok1 || !ok2 || from == 24*60 || from == to {
+		return 0, 0, false
+	}
+	return from, to, true
+}
+
+func parseClock(s string) (int, bool) {
+	if len(s) != 5 || s[2] != ':' {
+		return 0, false
+	}
+	h, err1 := strconv.Atoi(s[:2])
+	m, err2 := strconv.Atoi(s[3:])
+	if err1 != nil || err2 != nil || s[0] == '+' || s[0] == '-' || s[3] == '+' || s[3] == '-' {
+		return 0, false
+	}
+	if h == 24 && m == 0 {
+		return 24 * 60, true
+	}
+	if h > 23 || m > 59 {
+		return 0, false
+	}
+	return h*60 + m, true
+}
+
+// --- Helper Functions ---
+
+// clipSet объединяет интервалы и обрезает их по bounds.
+func clipSet(ranges []TimeRange, bounds TimeRange) []TimeRange {
+	merged, _ := MergeOverlapping(ranges)
+
+	var result []TimeRange
+	for _, tr := range merged {
+		start, end := maxTime(tr.Start, bounds.Start), minTime(tr.End, bounds.End)
+		if start.Before(end) {
+			result = append(result, TimeRange{Start: start, End: end})
+		}
+	}
+	return normalizeAll(result)
+}
+
+// intersectSets пересекает два отсортированных объединенных набора.
+func intersectSets(a, b []TimeRange) []TimeRange {
+	var result []TimeRange
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		start, end := maxTime(a[i].Start, b[j].Start), minTime(a[i].End, b[j].End)
+		if start.Before(end) {
+			result = append(result, TimeRange{Start: start, End: end})
+		}
+		if a[i].End.Before(b[j].End) {
+			i++
+		} else {
+			j++
+		}
+	}
+	return normalizeAll(result)
+}
@@ -0,0 +1,162 @@
+package timerange
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// exprWeek - неделя с понедельника 2024-05-06 по понедельник 2024-05-13 в UTC
+var exprWeek = TimeRange{Start: mayAt(6, 0), End: mayAt(13, 0)}
+
+func TestCompileExprEvaluate(t *testing.T) {
+	holidays := SourceFunc(func(bounds TimeRange) []TimeRange {
+		return []TimeRange{mayRange(9, 0, 24)}
+	})
+	opts := ExprOptions{Calendars: map[string]Source{"holidays(ru)": holidays}}
+
+	tests := []struct {
+		name     string
+		expr     string
+		expected []TimeRange
+	}{
+		{
+			name:     "weekends",
+			expr:     "weekends",
+			expected: []TimeRange{{Start: mayRange(11, 0, 0).Start, End: exprWeek.End}},
+		},
+		{
+			name:     "day range",
+			expr:     "mon-tue",
+			expected: []TimeRange{{Start: exprWeek.Start, End: mayRange(8, 0, 0).Start}},
+		},
+		{
+			name:     "wrapping day range",
+			expr:     "sat-mon",
+			expected: []TimeRange{mayRange(6, 0, 24), {Start: mayRange(11, 0, 0).Start, End: exprWeek.End}},
+		},
+		{
+			name: "weekdays during office hours",
+			expr: "weekdays & 09:00-18:00",
+			expected: []TimeRange{
+				mayRange(6, 9, 18), mayRange(7, 9, 18), mayRange(8, 9, 18), mayRange(9, 9, 18), mayRange(10, 9, 18),
+			},
+		},
+		{
+			name: "difference with calendar",
+			expr: "weekdays & 09:00-18:00 & !holidays(ru)",
+			expected: []TimeRange{
+				mayRange(6, 9, 18), mayRange(7, 9, 18), mayRange(8, 9, 18), mayRange(10, 9, 18),
+			},
+		},
+		{
+			name: "union with ISO interval",
+			expr: "fri & 09:00-10:00 | 2024-05-12T10:00/PT2H",
+			expected: []TimeRange{
+				mayRange(10, 9, 10), mayRange(12, 10, 12),
+			},
+		},
+		{
+			name: "parentheses change precedence",
+			expr: "sun & (09:00-10:00 | 20:00-21:00)",
+			expected: []TimeRange{
+				mayRange(12, 9, 10), mayRange(12, 20, 21),
+			},
+		},
+		{
+			name: "overnight window is clipped to bounds",
+			expr: "mon & 22:00-06:00",
+			expected: []TimeRange{
+				mayRange(6, 0, 6), mayRange(6, 22, 24),
+			},
+		},
+		{
+			name: "window in another zone",
+			expr: "sat & 09:00-18:00 Europe/Moscow",
+			expected: []TimeRange{
+				mayRange(11, 6, 15),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := CompileExpr(tt.expr, opts)
+			if err != nil {
+				t.Fatalf("CompileExpr() error = %v", err)
+			}
+			if result := e.Evaluate(exprWeek); !compareRanges(result, tt.expected) {
+				t.Errorf("Evaluate() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCompileExprLocation(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	e, err := CompileExpr("sat", ExprOptions{Location: moscow})
+	if err != nil {
+		t.Fatalf("CompileExpr() error = %v", err)
+	}
+
+	expected := []TimeRange{{
+		Start: time.Date(2024, 5, 11, 0, 0, 0, 0, moscow),
+		End:   time.Date(2024, 5, 12, 0, 0, 0, 0, moscow),
+	}}
+	if result := e.Evaluate(exprWeek); !compareRanges(result, expected) {
+		t.Errorf("Evaluate() = %v, want %v", result, expected)
+	}
+}
+
+func TestCompileExprNested(t *testing.T) {
+	office, err := CompileExpr("weekdays & 09:00-18:00", ExprOptions{})
+	if err != nil {
+		t.Fatalf("CompileExpr() error = %v", err)
+	}
+	e, err := CompileExpr("!office", ExprOptions{Calendars: map[string]Source{"office": office}})
+	if err != nil {
+		t.Fatalf("CompileExpr() error = %v", err)
+	}
+
+	var busy time.Duration
+	for _, tr := range e.Evaluate(exprWeek) {
+		busy += tr.Duration()
+	}
+	if expected := 7*24*time.Hour - 5*9*time.Hour; busy != expected {
+		t.Errorf("Evaluate() covers %v, want %v", busy, expected)
+	}
+}
+
+func TestCompileExprStripsMonotonic(t *testing.T) {
+	now := time.Now()
+	bounds := TimeRange{Start: now, End: now.Add(time.Hour)}
+	e, err := CompileExpr("mon-sun & mon-sun", ExprOptions{})
+	if err != nil {
+		t.Fatalf("CompileExpr() error = %v", err)
+	}
+	expected := TimeRange{Start: now.Round(0), End: now.Add(time.Hour).Round(0)}
+	if got := e.Evaluate(bounds); len(got) != 1 || got[0] != expected {
+		t.Errorf("Evaluate() = %v, want %v", got, expected)
+	}
+}
+
+func TestCompileExprErrors(t *testing.T) {
+	invalid := []string{
+		"",
+		"weekdays &",
+		"| weekends",
+		"(weekdays",
+		"weekdays)",
+		"holidays(ru)",
+		"holidays(ru",
+		"25:00-26:00",
+		"09:00-18:00 Nowhere/City",
+		"2024-13-01/PT1H",
+		"someday",
+	}
+	for _, expr := range invalid {
+		if _, err := CompileExpr(expr, ExprOptions{}); !errors.Is(err, ErrInvalidExpr) {
+			t.Errorf("CompileExpr(%q) error = %v, want ErrInvalidExpr", expr, err)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package timerange
+
+import (
+	"fmt"
+	"time"
+)
+
+// FiscalScheme задает деление финансового года на 12 периодов.
+type FiscalScheme int
+
+const (
+	// FiscalMonths - календарные месяцы, отсчитываемые от начала финансового года.
+	FiscalMonths FiscalScheme = iota
+	// Fiscal445, Fiscal454 и Fiscal544 - периоды из 4 и 5 недель в каждом квартале.
+	Fiscal445
+	Fiscal454
+	Fiscal544
+)
+
+var fiscalPatterns = map[FiscalScheme][3]int{
+	Fiscal445: {4, 4, 5},
+	Fiscal454: {4, 5, 4},
+	Fiscal544: {5, 4, 4},
+}
+
+// YearStartRule возвращает начало финансового года year в зоне loc.
+// Финансовый год именуется по календарному году, в котором он начинается.
+type YearStartRule func(year int, loc *time.Location) time.Time
+
+func FixedYearStart(month time.Month, day int) YearStartRule {
+	return func(year int, loc *time.Location) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, loc)
+	}
+}
+
+// LastWeekdayOf - последний weekday месяца, например последняя суббота января.
+func LastWeekdayOf(month time.Month, weekday time.Weekday) YearStartRule {
+	return func(year int, loc *time.Location) time.Time {
+		last := time.Date(year, month+1, 0, 0, 0, 0, 0, loc)
+		offset := (int(last.Weekday()) - int(weekday) + 7) % 7
+		return last.AddDate(0, 0, -offset)
+	}
+}
+
+// NearestWeekdayTo - weekday, ближайший к заданной дате, например суббота,
+// ближайшая к 31 января.
+func NearestWeekdayTo(month time.Month, day int, weekday time.Weekday) YearStartRule {
+	return func(year int, loc *time.Location) time.Time {
+		date := time.Date(year, month, day, 0, 0, 0, 0, loc)
+		offset := (int(weekday) - int(date.Weekday()) + 7) % 7
+		if offset > 3 {
+			offset -= 7
+		}
+		return date.AddDate(0, 0, offset)
+	}
+}
+
+type FiscalCalendar struct {
+	Scheme    FiscalScheme
+	YearStart YearStartRule  // по умолчанию 1 января
+	Location  *time.Location // по умолчанию UTC
+}
+
+// FiscalPeriod - положение момента в финансовом календаре.
+type FiscalPeriod struct {
+	Year    int
+	Quarter int
+	Period  int
+	Week    int
+}
+
+func (p FiscalPeriod) String() string {
+	return fmt.Sprintf("FY%d-Q%d-P%02d-W%02d", p.Year, p.Quarter, p.Period, p.Week)
+}
+
+// --- Fiscal Ranges ---
+
+func (c FiscalCalendar) Year(fy int) TimeRange {
+	return TimeRange{Start: c.yearStart(fy), End: c.yearStart(fy + 1)}
+}
+
+func (c FiscalCalendar) Quarter(fy, q int) (TimeRange, error) {
+	if q < 1 || q > 4 {
+		return TimeRange{}, ErrInvalidArgument
+	}
+	first, _ := c.Period(fy, 3*q-2)
+	last, _ := c.Period(fy, 3*q)
+	return TimeRange{Start: first.Start, End: last.End}, nil
+}
+
+// Period возвращает период p (1-12). В 53-недельном году дополнительная
+// неделя добавляется к последнему периоду.
+func (c FiscalCalendar) Period(fy, p int) (TimeRange, error) {
+	if p < 1 || p > 12 {
+		return TimeRange{}, ErrInvalidArgument
+	}
+	year := c.Year(fy)
+	end := year.End
+	if p < 12 {
+		end = c.periodStart(year.Start, p+1)
+	}
+	return TimeRange{Start: c.periodStart(year.Start, p), End: end}, nil
+}
+
+// Week возвращает неделю w (1-Weeks(fy)). Если год не делится на целые
+// недели, остаток дней входит в последнюю неделю.
+func (c FiscalCalendar) Week(fy, w int) (TimeRange, error) {
+	weeks := c.Weeks(fy)
+	if w < 1 || w > weeks {
+		return TimeRange{}, ErrInvalidArgument
+	}
+	year := c.Year(fy)
+	start := year.Start.AddDate(0, 0, 7*(w-1))
+	end := year.End
+	if w < weeks {
+		end = start.AddDate(0, 0, 7)
+	}
+	return TimeRange{Start: start, End: end}, nil
+}
+
+// Weeks возвращает число целых недель между началами годов. Для недельных
+// схем начало года должно приходиться на один и тот же день недели
+// (LastWeekdayOf, NearestWeekdayTo), тогда год состоит из 52 или 53 недель.
+func (c FiscalCalendar) Weeks(fy int) int {
+	year := c.Year(fy)
+	return civilDays(year.Start, year.End) / 7
+}
+
+// Is53WeekYear сообщает, что в недельной схеме год содержит 53-ю неделю.
+// Для FiscalMonths всегда false.
+func (c FiscalCalendar) Is53WeekYear(fy int) bool {
+	if c.Scheme == FiscalMonths {
+		return false
+	}
+	return c.Weeks(fy) == 53
+}
+
+// Locate возвращает финансовый год, квартал, период и неделю, содержащие t.
+func (c FiscalCalendar) Locate(t time.Time) FiscalPeriod {
+	fy := t.In(c.location()).Year()
+	for !t.Before(c.yearStart(fy + 1)) {
+		fy++
+	}
+	for t.Before(c.yearStart(fy)) {
+		fy--
+	}
+
+	year := c.Year(fy)
+	period := 12
+	for p := 1; p < 12; p++ {
+		if t.Before(c.periodStart(year.Start, p+1)) {
+			period = p
+			break
+		}
+	}
+
+	return FiscalPeriod{
+		Year:    fy,
+		Quarter: (period-1)/3 + 1,
+		Period:  period,
+		Week:    min(civilDays(year.Start, t.In(c.location()))/7+1, c.Weeks(fy)),
+	}
+}
+
+// --- Helper Functions ---
+
+func (c FiscalCalendar) location() *time.Location {
+	if c.Location == nil {
+		return time.UTC
+	}
+	return c.Location
+}
+
+func (c FiscalCalendar) yearStart(fy int) time.Time {
+	rule := c.YearStart
+	if rule == nil {
+		rule = FixedYearStart(time.January, 1)
+	}
+	return rule(fy, c.location())
+}
+
+func (c FiscalCalendar) periodStart(yearStart time.Time, p int) time.Time {
+	pattern, ok := fiscalPatterns[c.Scheme]
+	if !ok {
+		return addUnitsClamped(yearStart, UnitMonth, p-1)
+	}
+
+	weeks := 0
+	for i := 0; i < p-1; i++ {
+		weeks += pattern[i%3]
+	}
+	return yearStart.AddDate(0, 0, 7*weeks)
+}
+
+// civilDays возвращает число календарных дней между датами a и b без учета
+// времени суток и переходов на летнее время.
+func civilDays(a, b time.Time) int {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	from := time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC)
+	to := time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from) / (24 * time.Hour))
+}
@@ -0,0 +1,186 @@
+package timerange
+
+import (
+	"testing"
+	"time"
+)
+
+func TestYearStartRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   YearStartRule
+		year   int
+		expect time.Time
+	}{
+		{
+			name:   "fixed date",
+			rule:   FixedYearStart(time.April, 1),
+			year:   2024,
+			expect: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "last saturday of january",
+			rule:   LastWeekdayOf(time.January, time.Saturday),
+			year:   2024,
+			expect: time.Date(2024, 1, 27, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "saturday nearest to january 31",
+			rule:   NearestWeekdayTo(time.January, 31, time.Saturday),
+			year:   2024,
+			expect: time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule(tt.year, time.UTC); !got.Equal(tt.expect) {
+				t.Errorf("rule(%d) = %v, want %v", tt.year, got, tt.expect)
+			}
+		})
+	}
+}
+
+func TestFiscalCalendar445(t *testing.T) {
+	cal := FiscalCalendar{
+		Scheme:    Fiscal445,
+		YearStart: LastWeekdayOf(time.January, time.Saturday),
+		Location:  time.UTC,
+	}
+
+	t.Run("regular year", func(t *testing.T) {
+		if weeks := cal.Weeks(2024); weeks != 52 {
+			t.Errorf("Weeks(2024) = %d, want 52", weeks)
+		}
+
+		expectedWeeks := []int{4, 4, 5, 4, 4, 5, 4, 4, 5, 4, 4, 5}
+		for p, weeks := range expectedWeeks {
+			period, err := cal.Period(2024, p+1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if period.Duration() != time.Duration(weeks)*7*24*time.Hour {
+				t.Errorf("Period(2024, %d) = %v, want %d weeks", p+1, period.Duration(), weeks)
+			}
+		}
+
+		q1, err := cal.Quarter(2024, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q1.Duration() != 13*7*24*time.Hour {
+			t.Errorf("Quarter(2024, 1) = %v, want 13 weeks", q1.Duration())
+		}
+	})
+
+	t.Run("53-week year", func(t *testing.T) {
+		if !cal.Is53WeekYear(2020) {
+			t.Fatalf("Weeks(2020) = %d, want 53", cal.Weeks(2020))
+		}
+		last, _ := cal.Period(2020, 12)
+		if last.Duration() != 6*7*24*time.Hour {
+			t.Errorf("Period(2020, 12) = %v, want 6 weeks", last.Duration())
+		}
+		week, err := cal.Week(2020, 53)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !week.End.Equal(cal.Year(2021).Start) {
+			t.Errorf("Week(2020, 53) end = %v, want %v", week.End, cal.Year(2021).Start)
+		}
+	})
+
+	t.Run("locate", func(t *testing.T) {
+		// 2024-01-10 принадлежит финансовому 2023 году, начавшемуся 2023-01-28
+		got := cal.Locate(time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC))
+		expected := FiscalPeriod{Year: 2023, Quarter: 4, Period: 12, Week: 50}
+		if got != expected {
+			t.Errorf("Locate() = %v, want %v", got, expected)
+		}
+
+		got = cal.Locate(time.Date(2024, 1, 27, 0, 0, 0, 0, time.UTC))
+		expected = FiscalPeriod{Year: 2024, Quarter: 1, Period: 1, Week: 1}
+		if got != expected {
+			t.Errorf("Locate() = %v, want %v", got, expected)
+		}
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		if _, err := cal.Period(2024, 13); err != ErrInvalidArgument {
+			t.Errorf("Period() error = %v, want ErrInvalidArgument", err)
+		}
+		if _, err := cal.Quarter(2024, 0); err != ErrInvalidArgument {
+			t.Errorf("Quarter() error = %v, want ErrInvalidArgument", err)
+		}
+		if _, err := cal.Week(2024, 53); err != ErrInvalidArgument {
+			t.Errorf("Week() error = %v, want ErrInvalidArgument", err)
+		}
+	})
+}
+
+func TestFiscalCalendarMonths(t *testing.T) {
+	cal := FiscalCalendar{
+		Scheme:    FiscalMonths,
+		YearStart: FixedYearStart(time.April, 1),
+	}
+
+	q2, err := cal.Quarter(2024, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := TimeRange{
+		Start: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if !q2.Equal(expected) {
+		t.Errorf("Quarter(2024, 2) = %v, want %v", q2, expected)
+	}
+
+	got := cal.Locate(time.Date(2025, 2, 14, 0, 0, 0, 0, time.UTC))
+	if got.Year != 2024 || got.Period != 11 || got.Quarter != 4 {
+		t.Errorf("Locate() = %v, want FY2024 P11 Q4", got)
+	}
+}
+
+func TestFiscalWeeksDefaultCalendar(t *testing.T) {
+	tests := []struct {
+		name string
+		cal  FiscalCalendar
+	}{
+		{name: "zero value", cal: FiscalCalendar{}},
+		{name: "4-4-5 from january 1", cal: FiscalCalendar{Scheme: Fiscal445}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, fy := range []int{2023, 2024} {
+				if weeks := tt.cal.Weeks(fy); weeks != 52 {
+					t.Errorf("Weeks(%d) = %d, want 52", fy, weeks)
+				}
+				if tt.cal.Is53WeekYear(fy) {
+					t.Errorf("Is53WeekYear(%d) = true, want false", fy)
+				}
+			}
+
+			// Остаток дней входит в последнюю неделю
+			last, err := tt.cal.Week(2024, 52)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !last.End.Equal(tt.cal.Year(2025).Start) {
+				t.Errorf("Week(2024, 52) end = %v, want %v", last.End, tt.cal.Year(2025).Start)
+			}
+			got := tt.cal.Locate(time.Date(2024, 12, 31, 12, 0, 0, 0, time.UTC))
+			if got.Week != 52 {
+				t.Errorf("Locate() week = %d, want 52", got.Week)
+			}
+		})
+	}
+
+	t.Run("months scheme is never 53-week", func(t *testing.T) {
+		cal := FiscalCalendar{YearStart: LastWeekdayOf(time.January, time.Saturday)}
+		if cal.Is53WeekYear(2020) {
+			t.Errorf("Is53WeekYear(2020) = true, want false for FiscalMonths")
+		}
+	})
+}
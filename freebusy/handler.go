@@ -0,0 +1,215 @@
+// Package freebusy предоставляет встраиваемый net/http обработчик для операций
+// над интервалами: объединения, пересечения, вычитания, поиска промежутков и слотов.
+//
+// Тела запросов и ответов используют JSON-формат timerange.TimeRange:
+//
+//	mux.Handle("/freebusy/", http.StripPrefix("/freebusy", freebusy.NewHandler(freebusy.Config{})))
+package freebusy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/GiBi-develop/timerange/v1"
+)
+
+const (
+	DefaultMaxBodyBytes = 1 << 20
+	DefaultMaxRanges    = 10000
+)
+
+type Config struct {
+	MaxBodyBytes int64 // максимальный размер тела запроса, по умолчанию DefaultMaxBodyBytes
+	MaxRanges    int   // максимальное число интервалов в запросе, по умолчанию DefaultMaxRanges
+}
+
+type Handler struct {
+	cfg Config
+	mux *http.ServeMux
+}
+
+// --- Request and Response Bodies ---
+
+type RangesRequest struct {
+	Ranges []timerange.TimeRange `json:"ranges"`
+}
+
+type SubtractRequest struct {
+	Range    timerange.TimeRange   `json:"range"`
+	Subtract []timerange.TimeRange `json:"subtract"`
+}
+
+type GapsRequest struct {
+	Occupied []timerange.TimeRange `json:"occupied"`
+	Bounds   timerange.TimeRange   `json:"bounds"`
+}
+
+type SlotsRequest struct {
+	Occupied []timerange.TimeRange `json:"occupied"`
+	Bounds   timerange.TimeRange   `json:"bounds"`
+	Duration string                `json:"duration"` // формат time.ParseDuration, например "30m"
+}
+
+type RangesResponse struct {
+	Ranges []timerange.TimeRange `json:"ranges"`
+}
+
+type RangeResponse struct {
+	Range timerange.TimeRange `json:"range"`
+}
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// --- Handler ---
+
+func NewHandler(cfg Config) *Handler {
+	if cfg.MaxBodyBytes <= 0 {
+		cfg.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+	if cfg.MaxRanges <= 0 {
+		cfg.MaxRanges = DefaultMaxRanges
+	}
+
+	h := &Handler{cfg: cfg, mux: http.NewServeMux()}
+	h.mux.HandleFunc("POST /union", h.union)
+	h.mux.HandleFunc("POST /intersection", h.intersection)
+	h.mux.HandleFunc("POST /subtract", h.subtract)
+	h.mux.HandleFunc("POST /gaps", h.gaps)
+	h.mux.HandleFunc("POST /slots", h.slots)
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) union(w http.ResponseWriter, r *http.Request) {
+	var req RangesRequest
+	if !h.decode(w, r, &req) || !h.validate(w, req.Ranges) {
+		return
+	}
+
+	merged, err := timerange.MergeOverlapping(req.Ranges)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, RangesResponse{Ranges: nonNil(merged)})
+}
+
+func (h *Handler) intersection(w http.ResponseWriter, r *http.Request) {
+	var req RangesRequest
+	if !h.decode(w, r, &req) || !h.validate(w, req.Ranges) {
+		return
+	}
+
+	common, err := timerange.Intersection(req.Ranges)
+	switch {
+	case errors.Is(err, timerange.ErrNoIntersection):
+		writeError(w, http.StatusUnprocessableEntity, err)
+	case err != nil:
+		writeError(w, http.StatusBadRequest, err)
+	default:
+		writeJSON(w, http.StatusOK, RangeResponse{Range: common})
+	}
+}
+
+func (h *Handler) subtract(w http.ResponseWriter, r *http.Request) {
+	var req SubtractRequest
+	if !h.decode(w, r, &req) || !h.validate(w, append([]timerange.TimeRange{req.Range}, req.Subtract...)) {
+		return
+	}
+	writeJSON(w, http.StatusOK, RangesResponse{Ranges: nonNil(req.Range.SubtractAll(req.Subtract))})
+}
+
+func (h *Handler) gaps(w http.ResponseWriter, r *http.Request) {
+	var req GapsRequest
+	if !h.decode(w, r, &req) || !h.validate(w, append([]timerange.TimeRange{req.Bounds}, req.Occupied...)) {
+		return
+	}
+
+	gaps, err := timerange.FindGaps(req.Occupied, req.Bounds)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, RangesResponse{Ranges: nonNil(gaps)})
+}
+
+func (h *Handler) slots(w http.ResponseWriter, r *http.Request) {
+	var req SlotsRequest
+	if !h.decode(w, r, &req) || !h.validate(w, append([]timerange.TimeRange{req.Bounds}, req.Occupied...)) {
+		return
+	}
+
+	d, err := time.ParseDuration(req.Duration)
+	if err != nil || d <= 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid duration %q", req.Duration))
+		return
+	}
+	slots, err := timerange.FindSlots(req.Occupied, req.Bounds, d)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, RangesResponse{Ranges: nonNil(slots)})
+}
+
+// --- Helper Functions ---
+
+func (h *Handler) decode(w http.ResponseWriter, r *http.Request, dst any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.cfg.MaxBodyBytes))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(dst); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, err)
+		} else {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		}
+		return false
+	}
+	return true
+}
+
+func (h *Handler) validate(w http.ResponseWriter, ranges []timerange.TimeRange) bool {
+	if len(ranges) > h.cfg.MaxRanges {
+		writeError(w, http.StatusRequestEntityTooLarge,
+			fmt.Errorf("too many ranges: %d, limit %d", len(ranges), h.cfg.MaxRanges))
+		return false
+	}
+	for i, tr := range ranges {
+		if tr.Start.IsZero() && tr.End.IsZero() {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("range %d: empty time range", i))
+			return false
+		}
+		if _, err := timerange.New(tr.Start, tr.End); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("range %d: %w", i, err))
+			return false
+		}
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, ErrorResponse{Error: err.Error()})
+}
+
+func nonNil(ranges []timerange.TimeRange) []timerange.TimeRange {
+	if ranges == nil {
+		return []timerange.TimeRange{}
+	}
+	return ranges
+}
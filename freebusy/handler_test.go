@@ -0,0 +1,180 @@
+package freebusy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GiBi-develop/timerange/v1"
+)
+
+func post(t *testing.T, h http.Handler, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeRanges(t *testing.T, rec *httptest.ResponseRecorder) []timerange.TimeRange {
+	t.Helper()
+	var resp RangesResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp.Ranges
+}
+
+func TestHandler(t *testing.T) {
+	h := NewHandler(Config{})
+
+	t.Run("union", func(t *testing.T) {
+		rec := post(t, h, "/union", `{"ranges":[
+			{"start":"2023-01-01T09:00:00Z","end":"2023-01-01T11:00:00Z"},
+			{"start":"2023-01-01T10:00:00Z","end":"2023-01-01T12:00:00Z"}]}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+		}
+		ranges := decodeRanges(t, rec)
+		if len(ranges) != 1 || ranges[0].Duration() != 3*time.Hour {
+			t.Errorf("union = %v, want one 3h range", ranges)
+		}
+	})
+
+	t.Run("intersection without overlap", func(t *testing.T) {
+		rec := post(t, h, "/intersection", `{"ranges":[
+			{"start":"2023-01-01T09:00:00Z","end":"2023-01-01T10:00:00Z"},
+			{"start":"2023-01-01T11:00:00Z","end":"2023-01-01T12:00:00Z"}]}`)
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("status = %d, want 422", rec.Code)
+		}
+	})
+
+	t.Run("subtract", func(t *testing.T) {
+		rec := post(t, h, "/subtract", `{
+			"range":{"start":"2023-01-01T09:00:00Z","end":"2023-01-01T18:00:00Z"},
+			"subtract":[{"start":"2023-01-01T12:00:00Z","end":"2023-01-01T13:00:00Z"}]}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+		}
+		if ranges := decodeRanges(t, rec); len(ranges) != 2 {
+			t.Errorf("subtract = %v, want 2 ranges", ranges)
+		}
+	})
+
+	t.Run("gaps", func(t *testing.T) {
+		rec := post(t, h, "/gaps", `{
+			"bounds":{"start":"2023-01-01T09:00:00Z","end":"2023-01-01T18:00:00Z"},
+			"occupied":[{"start":"2023-01-01T09:00:00Z","end":"2023-01-01T17:00:00Z"}]}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+		}
+		ranges := decodeRanges(t, rec)
+		if len(ranges) != 1 || ranges[0].Duration() != time.Hour {
+			t.Errorf("gaps = %v, want one 1h gap", ranges)
+		}
+	})
+
+	t.Run("gaps ignore occupied outside bounds", func(t *testing.T) {
+		rec := post(t, h, "/gaps", `{
+			"bounds":{"start":"2023-01-01T09:00:00Z","end":"2023-01-01T18:00:00Z"},
+			"occupied":[{"start":"2023-01-01T20:00:00Z","end":"2023-01-01T21:00:00Z"}]}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+		}
+		ranges := decodeRanges(t, rec)
+		if len(ranges) != 1 || ranges[0].Duration() != 9*time.Hour {
+			t.Errorf("gaps = %v, want one 9h gap", ranges)
+		}
+	})
+
+	t.Run("slots", func(t *testing.T) {
+		rec := post(t, h, "/slots", `{
+			"bounds":{"start":"2023-01-01T09:00:00Z","end":"2023-01-01T11:00:00Z"},
+			"occupied":[],
+			"duration":"30m"}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+		}
+		if ranges := decodeRanges(t, rec); len(ranges) != 4 {
+			t.Errorf("slots = %v, want 4 slots", ranges)
+		}
+	})
+}
+
+func TestHandlerValidation(t *testing.T) {
+	h := NewHandler(Config{MaxBodyBytes: 512, MaxRanges: 2})
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+	}{
+		{
+			name:   "malformed json",
+			path:   "/union",
+			body:   `{"ranges":`,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "unknown field",
+			path:   "/union",
+			body:   `{"rangez":[]}`,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "inverted range",
+			path:   "/union",
+			body:   `{"ranges":[{"start":"2023-01-02T00:00:00Z","end":"2023-01-01T00:00:00Z"}]}`,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "missing bounds",
+			path:   "/gaps",
+			body:   `{"occupied":[]}`,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid duration",
+			path:   "/slots",
+			body:   `{"bounds":{"start":"2023-01-01T09:00:00Z","end":"2023-01-01T11:00:00Z"},"duration":"soon"}`,
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "too many ranges",
+			path: "/union",
+			body: `{"ranges":[
+				{"start":"2023-01-01T00:00:00Z","end":"2023-01-01T01:00:00Z"},
+				{"start":"2023-01-01T02:00:00Z","end":"2023-01-01T03:00:00Z"},
+				{"start":"2023-01-01T04:00:00Z","end":"2023-01-01T05:00:00Z"}]}`,
+			status: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:   "body too large",
+			path:   "/union",
+			body:   `{"ranges":[` + strings.Repeat(" ", 1024) + `]}`,
+			status: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := post(t, h, tt.path, tt.body)
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+		})
+	}
+
+	t.Run("method not allowed", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/union", nil))
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("status = %d, want 405", rec.Code)
+		}
+	})
+}
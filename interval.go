@@ -0,0 +1,194 @@
+package timerange
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// Interval - временной интервал с привязанным значением (смена, цена, владелец).
+type Interval[V any] struct {
+	TimeRange
+	Value V `json:"value"`
+}
+
+// Resolver объединяет значения пересекающихся интервалов.
+type Resolver[V any] func(a, b V) V
+
+func NewInterval[V any](start, end time.Time, value V) (Interval[V], error) {
+	tr, err := New(start, end)
+	if err != nil {
+		return Interval[V]{}, err
+	}
+	return Interval[V]{TimeRange: tr, Value: value}, nil
+}
+
+// --- Set Operations ---
+
+// Merge разбивает интервалы на непересекающиеся отрезки. Значения на
+// пересечениях сворачиваются через resolve в порядке начала интервалов.
+func Merge[V any](intervals []Interval[V], resolve Resolver[V]) []Interval[V] {
+	sorted := sortedIntervals(intervals)
+	if len(sorted) == 0 {
+		return nil
+	}
+
+	points := boundaries(sorted)
+	var (
+		result []Interval[V]
+		active []Interval[V]
+		next   int
+	)
+	for i := 0; i < len(points)-1; i++ {
+		from, to := points[i], points[i+1]
+
+		kept := active[:0]
+		for _, iv := range active {
+			if iv.End.After(from) {
+				kept = append(kept, iv)
+			}
+		}
+		active = kept
+		for next < len(sorted) && !sorted[next].Start.After(from) {
+			active = append(active, sorted[next])
+			next++
+		}
+		if len(active) == 0 {
+			continue
+		}
+
+		value := active[0].Value
+		for _, iv := range active[1:] {
+			value = resolve(value, iv.Value)
+		}
+		result = append(result, Interval[V]{
+			TimeRange: TimeRange{Start: from, End: to},
+			Value:     value,
+		})
+	}
+	return result
+}
+
+// Split делит каждый интервал на части длительностью d, сохраняя значение.
+func Split[V any](intervals []Interval[V], d time.Duration) []Interval[V] {
+	var result []Interval[V]
+	for _, iv := range intervals {
+		for _, part := range iv.SplitByDuration(d) {
+			result = append(result, Interval[V]{TimeRange: part, Value: iv.Value})
+		}
+	}
+	return result
+}
+
+// Overlay накладывает layer на base. Результат покрывает только base;
+// там, где есть слой, значение равно resolve(base, layer).
+func Overlay[V any](base, layer []Interval[V], resolve Resolver[V]) []Interval[V] {
+	var result []Interval[V]
+	for _, b := range sortedIntervals(base) {
+		group := []Interval[V]{b}
+		for _, l := range layer {
+			if !b.Overlaps(l.TimeRange) {
+				continue
+			}
+			group = append(group, Interval[V]{
+				TimeRange: TimeRange{
+					Start: maxTime(b.Start, l.Start),
+					End:   minTime(b.End, l.End),
+				},
+				Value: l.Value,
+			})
+		}
+		result = append(result, Merge(group, resolve)...)
+	}
+	return result
+}
+
+// Coalesce объединяет смежные и пересекающиеся интервалы с равными значениями.
+func Coalesce[V comparable](intervals []Interval[V]) []Interval[V] {
+	return CoalesceFunc(intervals, func(a, b V) bool { return a == b })
+}
+
+func CoalesceFunc[V any](intervals []Interval[V], equal func(a, b V) bool) []Interval[V] {
+	sorted := sortedIntervals(intervals)
+	if len(sorted) == 0 {
+		return nil
+	}
+
+	merged := []Interval[V]{sorted[0]}
+	for _, curr := range sorted[1:] {
+		last := &merged[len(merged)-1]
+		if !curr.Start.After(last.End) && equal(last.Value, curr.Value) {
+			if curr.End.After(last.End) {
+				last.End = curr.End
+			}
+		} else {
+			merged = append(merged, curr)
+		}
+	}
+	return merged
+}
+
+// --- JSON Support ---
+
+// MarshalJSON переопределяет метод, унаследованный от TimeRange, чтобы не терять Value.
+func (iv Interval[V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		Start     time.Time `json:"start"`
+		End       time.Time `json:"end"`
+		ISOString string    `json:"iso"`
+		Value     V         `json:"value"`
+	}{
+		Start:     iv.Start,
+		End:       iv.End,
+		ISOString: iv.ToISOString(),
+		Value:     iv.Value,
+	})
+}
+
+func (iv *Interval[V]) UnmarshalJSON(data []byte) error {
+	if err := iv.TimeRange.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	aux := &struct {
+		Value *V `json:"value"`
+	}{
+		Value: &iv.Value,
+	}
+	return json.Unmarshal(data, aux)
+}
+
+// --- Helper Functions ---
+
+// sortedIntervals возвращает копию непустых интервалов, упорядоченную по началу.
+// Порядок интервалов с одинаковым началом сохраняется.
+func sortedIntervals[V any](intervals []Interval[V]) []Interval[V] {
+	sorted := make([]Interval[V], 0, len(intervals))
+	for _, iv := range intervals {
+		if iv.Start.Before(iv.End) {
+			sorted = append(sorted, iv)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+	return sorted
+}
+
+// boundaries возвращает отсортированные уникальные границы интервалов.
+func boundaries[V any](intervals []Interval[V]) []time.Time {
+	points := make([]time.Time, 0, 2*len(intervals))
+	for _, iv := range intervals {
+		points = append(points, iv.Start, iv.End)
+	}
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].Before(points[j])
+	})
+
+	unique := points[:0]
+	for _, p := range points {
+		if len(unique) == 0 || !unique[len(unique)-1].Equal(p) {
+			unique = append(unique, p)
+		}
+	}
+	return unique
+}
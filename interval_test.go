@@ -0,0 +1,141 @@
+package timerange
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func hourRange(from, to int) TimeRange {
+	base := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	return TimeRange{
+		Start: base.Add(time.Duration(from) * time.Hour),
+		End:   base.Add(time.Duration(to) * time.Hour),
+	}
+}
+
+func TestMergeIntervals(t *testing.T) {
+	concat := func(a, b string) string { return a + "+" + b }
+
+	t.Run("overlapping values are resolved", func(t *testing.T) {
+		intervals := []Interval[string]{
+			{TimeRange: hourRange(0, 4), Value: "alice"},
+			{TimeRange: hourRange(2, 6), Value: "bob"},
+		}
+
+		result := Merge(intervals, concat)
+		expected := []Interval[string]{
+			{TimeRange: hourRange(0, 2), Value: "alice"},
+			{TimeRange: hourRange(2, 4), Value: "alice+bob"},
+			{TimeRange: hourRange(4, 6), Value: "bob"},
+		}
+		if !equalIntervals(result, expected) {
+			t.Errorf("Merge() = %v, want %v", result, expected)
+		}
+	})
+
+	t.Run("gaps are preserved", func(t *testing.T) {
+		intervals := []Interval[string]{
+			{TimeRange: hourRange(5, 6), Value: "b"},
+			{TimeRange: hourRange(0, 1), Value: "a"},
+		}
+
+		result := Merge(intervals, concat)
+		expected := []Interval[string]{
+			{TimeRange: hourRange(0, 1), Value: "a"},
+			{TimeRange: hourRange(5, 6), Value: "b"},
+		}
+		if !equalIntervals(result, expected) {
+			t.Errorf("Merge() = %v, want %v", result, expected)
+		}
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		if result := Merge[string](nil, concat); result != nil {
+			t.Errorf("Merge() = %v, want nil", result)
+		}
+	})
+}
+
+func TestSplitIntervals(t *testing.T) {
+	intervals := []Interval[int]{{TimeRange: hourRange(0, 3), Value: 7}}
+
+	result := Split(intervals, time.Hour)
+	if len(result) != 3 {
+		t.Fatalf("Split() len = %d, want 3", len(result))
+	}
+	for _, iv := range result {
+		if iv.Value != 7 || iv.Duration() != time.Hour {
+			t.Errorf("Split() part = %v, want 1h with value 7", iv)
+		}
+	}
+}
+
+func TestOverlay(t *testing.T) {
+	base := []Interval[float64]{{TimeRange: hourRange(0, 10), Value: 100}}
+	layer := []Interval[float64]{
+		{TimeRange: hourRange(2, 4), Value: 0.5},
+		{TimeRange: hourRange(8, 12), Value: 0.9},
+	}
+
+	result := Overlay(base, layer, func(price, discount float64) float64 {
+		return price * discount
+	})
+	expected := []Interval[float64]{
+		{TimeRange: hourRange(0, 2), Value: 100},
+		{TimeRange: hourRange(2, 4), Value: 50},
+		{TimeRange: hourRange(4, 8), Value: 100},
+		{TimeRange: hourRange(8, 10), Value: 90},
+	}
+	if !equalIntervals(result, expected) {
+		t.Errorf("Overlay() = %v, want %v", result, expected)
+	}
+}
+
+func TestCoalesce(t *testing.T) {
+	intervals := []Interval[string]{
+		{TimeRange: hourRange(2, 4), Value: "a"},
+		{TimeRange: hourRange(0, 2), Value: "a"},
+		{TimeRange: hourRange(4, 5), Value: "b"},
+		{TimeRange: hourRange(6, 7), Value: "b"},
+	}
+
+	result := Coalesce(intervals)
+	expected := []Interval[string]{
+		{TimeRange: hourRange(0, 4), Value: "a"},
+		{TimeRange: hourRange(4, 5), Value: "b"},
+		{TimeRange: hourRange(6, 7), Value: "b"},
+	}
+	if !equalIntervals(result, expected) {
+		t.Errorf("Coalesce() = %v, want %v", result, expected)
+	}
+}
+
+func TestIntervalJSON(t *testing.T) {
+	iv := Interval[string]{TimeRange: hourRange(0, 8), Value: "night shift"}
+
+	data, err := json.Marshal(iv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var restored Interval[string]
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatal(err)
+	}
+	if !restored.Equal(iv.TimeRange) || restored.Value != iv.Value {
+		t.Errorf("JSON roundtrip failed: got %v, want %v", restored, iv)
+	}
+}
+
+func equalIntervals[V comparable](a, b []Interval[V]) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i].TimeRange) || a[i].Value != b[i].Value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,147 @@
+package timerange
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidISOInterval = errors.New("invalid ISO 8601 interval")
+
+// ISODuration - длительность ISO 8601 (P1Y2M3DT4H5M6S). Годы, месяцы и дни
+// календарные и прибавляются через AddDate, остальное - точная длительность.
+type ISODuration struct {
+	Years, Months, Days int
+	Clock               time.Duration
+}
+
+// ParseISODuration разбирает длительность вида P1Y2M10DT2H30M, P2W или PT0.5S.
+func ParseISODuration(s string) (ISODuration, error) {
+	invalid := fmt.Errorf("%w: duration %q", ErrInvalidISOInterval, s)
+
+	rest, ok := strings.CutPrefix(strings.ToUpper(s), "P")
+	if !ok || rest == "" {
+		return ISODuration{}, invalid
+	}
+	datePart, timePart, hasTime := strings.Cut(rest, "T")
+	if hasTime && timePart == "" {
+		return ISODuration{}, invalid
+	}
+
+	var d ISODuration
+	for _, part := range []struct {
+		text  string
+		units string
+	}{{datePart, "YMWD"}, {timePart, "HMS"}} {
+		text, lastUnit := part.text, -1
+		for text != "" {
+			i := strings.IndexAny(text, part.units)
+			if i <= 0 {
+				return ISODuration{}, invalid
+			}
+			unit := strings.IndexByte(part.units, text[i])
+			if unit <= lastUnit {
+				return ISODuration{}, invalid
+			}
+			lastUnit = unit
+
+			number := text[:i]
+			text = text[i+1:]
+			if part.units == "HMS" {
+				value, err := strconv.ParseFloat(number, 64)
+				if err != nil || value < 0 {
+					return ISODuration{}, invalid
+				}
+				scale := []time.Duration{time.Hour, time.Minute, time.Second}[unit]
+				d.Clock += time.Duration(value * float64(scale))
+				continue
+			}
+
+			value, err := strconv.Atoi(number)
+			if err != nil || value < 0 {
+				return ISODuration{}, invalid
+			}
+			switch part.units[unit] {
+			case 'Y':
+				d.Years = value
+			case 'M':
+				d.Months = value
+			case 'W':
+				d.Days += 7 * value
+			case 'D':
+				d.Days += value
+			}
+		}
+	}
+	return d, nil
+}
+
+// AddTo прибавляет длительность к t; sign -1 вычитает ее.
+func (d ISODuration) AddTo(t time.Time, sign int) time.Time {
+	return t.AddDate(sign*d.Years, sign*d.Months, sign*d.Days).Add(time.Duration(sign) * d.Clock)
+}
+
+// ParseISOInterval разбирает интервал ISO 8601 в формах start/end,
+// start/duration и duration/end. Моменты без смещения трактуются в зоне loc
+// (nil - UTC).
+func ParseISOInterval(s string, loc *time.Location) (TimeRange, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	first, second, ok := strings.Cut(s, "/")
+	if !ok {
+		return TimeRange{}, fmt.Errorf("%w: %q", ErrInvalidISOInterval, s)
+	}
+
+	var start, end time.Time
+	switch {
+	case strings.HasPrefix(first, "P"):
+		d, err := ParseISODuration(first)
+		if err != nil {
+			return TimeRange{}, err
+		}
+		if end, err = parseISOInstant(second, loc); err != nil {
+			return TimeRange{}, err
+		}
+		start = d.AddTo(end, -1)
+	case strings.HasPrefix(second, "P"):
+		var err error
+		if start, err = parseISOInstant(first, loc); err != nil {
+			return TimeRange{}, err
+		}
+		d, err := ParseISODuration(second)
+		if err != nil {
+			return TimeRange{}, err
+		}
+		end = d.AddTo(start, 1)
+	default:
+		var err error
+		if start, err = parseISOInstant(first, loc); err != nil {
+			return TimeRange{}, err
+		}
+		if end, err = parseISOInstant(second, loc); err != nil {
+			return TimeRange{}, err
+		}
+	}
+	return New(start, end)
+}
+
+var isoLocalLayouts = []string{
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+func parseISOInstant(s string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range isoLocalLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: instant %q", ErrInvalidISOInterval, s)
+}
@@ -0,0 +1,98 @@
+package timerange
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseISODuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected ISODuration
+	}{
+		{"PT2H", ISODuration{Clock: 2 * time.Hour}},
+		{"PT1H30M", ISODuration{Clock: 90 * time.Minute}},
+		{"PT0.5S", ISODuration{Clock: 500 * time.Millisecond}},
+		{"P1M", ISODuration{Months: 1}},
+		{"P1Y2M10DT2H", ISODuration{Years: 1, Months: 2, Days: 10, Clock: 2 * time.Hour}},
+		{"P2W", ISODuration{Days: 14}},
+	}
+	for _, tt := range tests {
+		got, err := ParseISODuration(tt.input)
+		if err != nil {
+			t.Errorf("ParseISODuration(%q) error = %v", tt.input, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("ParseISODuration(%q) = %+v, want %+v", tt.input, got, tt.expected)
+		}
+	}
+
+	for _, input := range []string{"", "P", "PT", "2H", "PT2X", "P1D2Y", "PTH", "P-1D"} {
+		if _, err := ParseISODuration(input); !errors.Is(err, ErrInvalidISOInterval) {
+			t.Errorf("ParseISODuration(%q) error = %v, want ErrInvalidISOInterval", input, err)
+		}
+	}
+}
+
+func TestParseISOInterval(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+
+	tests := []struct {
+		input    string
+		expected TimeRange
+	}{
+		{
+			input: "2024-12-31T10:00:00Z/2024-12-31T12:00:00Z",
+			expected: TimeRange{
+				Start: time.Date(2024, 12, 31, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2024, 12, 31, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			input: "2024-12-31T10:00/PT2H",
+			expected: TimeRange{
+				Start: time.Date(2024, 12, 31, 10, 0, 0, 0, moscow),
+				End:   time.Date(2024, 12, 31, 12, 0, 0, 0, moscow),
+			},
+		},
+		{
+			input: "P1M/2024-03-31",
+			expected: TimeRange{
+				Start: time.Date(2024, 3, 2, 0, 0, 0, 0, moscow),
+				End:   time.Date(2024, 3, 31, 0, 0, 0, 0, moscow),
+			},
+		},
+		{
+			input: "2024-01-01/P1D",
+			expected: TimeRange{
+				Start: time.Date(2024, 1, 1, 0, 0, 0, 0, moscow),
+				End:   time.Date(2024, 1, 2, 0, 0, 0, 0, moscow),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseISOInterval(tt.input, moscow)
+			if err != nil {
+				t.Fatalf("ParseISOInterval() error = %v", err)
+			}
+			if !got.Equal(tt.expected) {
+				t.Errorf("ParseISOInterval() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, input := range []string{"2024-01-01", "yesterday/P1D", "2024-01-01/soon"} {
+			if _, err := ParseISOInterval(input, nil); !errors.Is(err, ErrInvalidISOInterval) {
+				t.Errorf("ParseISOInterval(%q) error = %v, want ErrInvalidISOInterval", input, err)
+			}
+		}
+		if _, err := ParseISOInterval("2024-01-02/2024-01-01", nil); err != ErrInvalidRange {
+			t.Errorf("ParseISOInterval() error = %v, want ErrInvalidRange", err)
+		}
+	})
+}
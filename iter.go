@@ -0,0 +1,151 @@
+package timerange
+
+import (
+	"iter"
+	"time"
+)
+
+// --- Lazy Iteration ---
+
+// SplitByDurationSeq - ленивый вариант SplitByDuration.
+func (tr TimeRange) SplitByDurationSeq(d time.Duration) iter.Seq[TimeRange] {
+	return func(yield func(TimeRange) bool) {
+		if d <= 0 {
+			yield(tr)
+			return
+		}
+
+		for current := tr.Start; current.Before(tr.End); {
+			next := current.Add(d)
+			if next.After(tr.End) {
+				next = tr.End
+			}
+			if !yield(TimeRange{Start: current, End: next}) {
+				return
+			}
+			current = next
+		}
+	}
+}
+
+// SplitByCalendarSeq - ленивый вариант SplitByCalendar.
+func (tr TimeRange) SplitByCalendarSeq(unit CalendarUnit, loc *time.Location) iter.Seq[TimeRange] {
+	return func(yield func(TimeRange) bool) {
+		if !unit.valid() {
+			yield(tr)
+			return
+		}
+		loc = rangeLocation(tr, loc)
+
+		current := tr.Start
+		boundary := startOfUnit(tr.Start, unit, loc, time.Monday)
+		for current.Before(tr.End) {
+			boundary = addUnits(boundary, unit, 1)
+			next := minTime(boundary, tr.End)
+			if !yield(TimeRange{Start: current, End: next}) {
+				return
+			}
+			current = next
+		}
+	}
+}
+
+// UnionSeq объединяет пересекающиеся и смежные интервалы потока,
+// отсортированного по началу, не загружая его в память целиком.
+func UnionSeq(sorted iter.Seq[TimeRange]) iter.Seq[TimeRange] {
+	return func(yield func(TimeRange) bool) {
+		var (
+			last    TimeRange
+			started bool
+		)
+		for curr := range sorted {
+			switch {
+			case !started:
+				last, started = curr, true
+			case !curr.Start.After(last.End):
+				last.End = maxTime(last.End, curr.End)
+			default:
+				if !yield(last) {
+					return
+				}
+				last = curr
+			}
+		}
+		if started {
+			yield(last)
+		}
+	}
+}
+
+// FindGapsSeq - ленивый вариант FindGaps для потока, отсортированного по началу.
+// Промежутки ограничиваются bounds.
+func FindGapsSeq(sorted iter.Seq[TimeRange], bounds TimeRange) iter.Seq[TimeRange] {
+	return func(yield func(TimeRange) bool) {
+		previousEnd := bounds.Start
+		for tr := range UnionSeq(sorted) {
+			if !tr.Start.Before(bounds.End) {
+				break
+			}
+			if tr.Start.After(previousEnd) {
+				if !yield(TimeRange{Start: previousEnd, End: tr.Start}) {
+					return
+				}
+			}
+			previousEnd = maxTime(previousEnd, tr.End)
+		}
+
+		if previousEnd.Before(bounds.End) {
+			yield(TimeRange{Start: previousEnd, End: bounds.End})
+		}
+	}
+}
+
+// MergeSorted сливает несколько потоков, отсортированных по началу, в один
+// отсортированный поток. Пересекающиеся интервалы не объединяются - для этого
+// результат можно передать в UnionSeq.
+func MergeSorted(seqs ...iter.Seq[TimeRange]) iter.Seq[TimeRange] {
+	return func(yield func(TimeRange) bool) {
+		type source struct {
+			next func() (TimeRange, bool)
+			stop func()
+			head TimeRange
+		}
+
+		sources := make([]*source, 0, len(seqs))
+		defer func() {
+			for _, src := range sources {
+				src.stop()
+			}
+		}()
+
+		var active []*source
+		for _, seq := range seqs {
+			next, stop := iter.Pull(seq)
+			src := &source{next: next, stop: stop}
+			sources = append(sources, src)
+			if head, ok := next(); ok {
+				src.head = head
+				active = append(active, src)
+			}
+		}
+
+		for len(active) > 0 {
+			earliest := 0
+			for i, src := range active[1:] {
+				if src.head.Start.Before(active[earliest].head.Start) {
+					earliest = i + 1
+				}
+			}
+
+			src := active[earliest]
+			if !yield(src.head) {
+				return
+			}
+			if head, ok := src.next(); ok {
+				src.head = head
+			} else {
+				active = append(active[:earliest], active[earliest+1:]...)
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package timerange
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSplitByDurationSeq(t *testing.T) {
+	year := TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	count := 0
+	for part := range year.SplitByDurationSeq(time.Second) {
+		if part.Duration() != time.Second {
+			t.Fatalf("part duration = %v, want 1s", part.Duration())
+		}
+		count++
+		if count == 1000 {
+			break
+		}
+	}
+	if count != 1000 {
+		t.Errorf("iterated %d parts, want 1000", count)
+	}
+}
+
+func TestUnionSeq(t *testing.T) {
+	sorted := []TimeRange{hourRange(0, 2), hourRange(1, 3), hourRange(3, 4), hourRange(6, 7)}
+
+	result := slices.Collect(UnionSeq(slices.Values(sorted)))
+	expected := []TimeRange{hourRange(0, 4), hourRange(6, 7)}
+	if !compareRanges(result, expected) {
+		t.Errorf("UnionSeq() = %v, want %v", result, expected)
+	}
+}
+
+func TestFindGapsSeq(t *testing.T) {
+	occupied := []TimeRange{hourRange(1, 2), hourRange(4, 5), hourRange(9, 12)}
+
+	result := slices.Collect(FindGapsSeq(slices.Values(occupied), hourRange(0, 10)))
+	expected := []TimeRange{hourRange(0, 1), hourRange(2, 4), hourRange(5, 9)}
+	if !compareRanges(result, expected) {
+		t.Errorf("FindGapsSeq() = %v, want %v", result, expected)
+	}
+}
+
+func TestMergeSorted(t *testing.T) {
+	a := []TimeRange{hourRange(0, 1), hourRange(4, 5)}
+	b := []TimeRange{hourRange(2, 3), hourRange(6, 7)}
+	c := []TimeRange{hourRange(3, 4)}
+
+	result := slices.Collect(MergeSorted(slices.Values(a), slices.Values(b), slices.Values(c)))
+	expected := []TimeRange{hourRange(0, 1), hourRange(2, 3), hourRange(3, 4), hourRange(4, 5), hourRange(6, 7)}
+	if !compareRanges(result, expected) {
+		t.Errorf("MergeSorted() = %v, want %v", result, expected)
+	}
+
+	t.Run("early stop", func(t *testing.T) {
+		for tr := range MergeSorted(slices.Values(a), slices.Values(b)) {
+			if !tr.Equal(hourRange(0, 1)) {
+				t.Errorf("first = %v, want %v", tr, hourRange(0, 1))
+			}
+			break
+		}
+	})
+}
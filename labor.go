@@ -0,0 +1,206 @@
+package timerange
+
+import (
+	"fmt"
+	"time"
+)
+
+// Shift - смена: отрезки работы, разделенные короткими перерывами.
+type Shift struct {
+	Range TimeRange   `json:"range"` // от начала первого до конца последнего отрезка
+	Work  []TimeRange `json:"work"`
+}
+
+// Worked возвращает отработанное время без перерывов.
+func (s Shift) Worked() time.Duration {
+	var total time.Duration
+	for _, tr := range s.Work {
+		total += tr.Duration()
+	}
+	return total
+}
+
+// GroupShifts объединяет отрезки работы в смены: перерывы не длиннее
+// maxBreak остаются внутри смены.
+func GroupShifts(schedule []TimeRange, maxBreak time.Duration) []Shift {
+	work, _ := MergeOverlapping(schedule)
+	work = dropEmpty(work)
+	grouped, err := MergeWithOptions(work, MergeOptions{Tolerance: maxBreak})
+	if err != nil {
+		return nil
+	}
+
+	shifts := make([]Shift, 0, len(grouped.Ranges))
+	for _, m := range grouped.Ranges {
+		shift := Shift{Range: m.Range}
+		for _, i := range m.Sources {
+			shift.Work = append(shift.Work, work[i])
+		}
+		shifts = append(shifts, shift)
+	}
+	return shifts
+}
+
+// ShiftViolation - нарушение правила с интервалами, которые его вызвали.
+type ShiftViolation struct {
+	Rule    string      `json:"rule"`
+	Ranges  []TimeRange `json:"ranges"`
+	Message string      `json:"message"`
+}
+
+// ShiftRule проверяет упорядоченные смены одного работника.
+type ShiftRule interface {
+	Name() string
+	Check(shifts []Shift) []ShiftViolation
+}
+
+// LaborRules - набор правил для графика одного работника.
+type LaborRules struct {
+	// MaxBreak - перерывы не длиннее этого значения не разделяют смены.
+	MaxBreak time.Duration
+	Rules    []ShiftRule
+}
+
+// Validate группирует график в смены и возвращает нарушения всех правил
+// в порядке их перечисления.
+func (l LaborRules) Validate(schedule []TimeRange) []ShiftViolation {
+	shifts := GroupShifts(schedule, l.MaxBreak)
+
+	var violations []ShiftViolation
+	for _, rule := range l.Rules {
+		violations = append(violations, rule.Check(shifts)...)
+	}
+	return violations
+}
+
+// --- Rules ---
+
+// MinRest - минимальный отдых между концом смены и началом следующей.
+type MinRest struct {
+	Duration time.Duration
+}
+
+func (r MinRest) Name() string {
+	return "min rest"
+}
+
+func (r MinRest) Check(shifts []Shift) []ShiftViolation {
+	var violations []ShiftViolation
+	for i := 1; i < len(shifts); i++ {
+		prev, next := shifts[i-1].Range, shifts[i].Range
+		if rest := prev.Gap(next).Duration(); rest < r.Duration {
+			violations = append(violations, ShiftViolation{
+				Rule:    r.Name(),
+				Ranges:  []TimeRange{prev, next},
+				Message: fmt.Sprintf("rest %s is shorter than %s", rest, r.Duration),
+			})
+		}
+	}
+	return violations
+}
+
+// MaxShiftLength - максимальная длина смены вместе с перерывами.
+type MaxShiftLength struct {
+	Duration time.Duration
+}
+
+func (r MaxShiftLength) Name() string {
+	return "max shift length"
+}
+
+func (r MaxShiftLength) Check(shifts []Shift) []ShiftViolation {
+	var violations []ShiftViolation
+	for _, shift := range shifts {
+		if length := shift.Range.Duration(); length > r.Duration {
+			violations = append(violations, ShiftViolation{
+				Rule:    r.Name(),
+				Ranges:  []TimeRange{shift.Range},
+				Message: fmt.Sprintf("shift length %s exceeds %s", length, r.Duration),
+			})
+		}
+	}
+	return violations
+}
+
+// MaxConsecutiveDays - максимальное число календарных дней подряд с работой
+// в зоне Location (по умолчанию UTC).
+type MaxConsecutiveDays struct {
+	Days     int
+	Location *time.Location
+}
+
+func (r MaxConsecutiveDays) Name() string {
+	return "max consecutive days"
+}
+
+func (r MaxConsecutiveDays) Check(shifts []Shift) []ShiftViolation {
+	loc := r.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	var (
+		violations []ShiftViolation
+		streak     []TimeRange
+		days       int
+		lastDay    time.Time
+	)
+	flush := func() {
+		if days > r.Days {
+			violations = append(violations, ShiftViolation{
+				Rule:    r.Name(),
+				Ranges:  streak,
+				Message: fmt.Sprintf("%d consecutive working days exceed %d", days, r.Days),
+			})
+		}
+		streak, days = nil, 0
+	}
+
+	for _, shift := range shifts {
+		first := startOfUnit(shift.Range.Start, UnitDay, loc, time.Monday)
+		last := startOfUnit(shift.Range.End.Add(-time.Nanosecond), UnitDay, loc, time.Monday)
+
+		if days > 0 && first.After(addUnits(lastDay, UnitDay, 1)) {
+			flush()
+		}
+		for day := first; !day.After(last); day = addUnits(day, UnitDay, 1) {
+			if days == 0 || day.After(lastDay) {
+				days++
+				lastDay = day
+			}
+		}
+		streak = append(streak, shift.Range)
+	}
+	flush()
+	return violations
+}
+
+// MandatoryBreak требует перерыв не короче Break после каждых After часов
+// непрерывной работы. Более короткие перерывы работу не прерывают.
+type MandatoryBreak struct {
+	After time.Duration
+	Break time.Duration
+}
+
+func (r MandatoryBreak) Name() string {
+	return "mandatory break"
+}
+
+func (r MandatoryBreak) Check(shifts []Shift) []ShiftViolation {
+	tolerance := max(r.Break-time.Nanosecond, 0)
+
+	var violations []ShiftViolation
+	for _, shift := range shifts {
+		stretches, _ := MergeWithOptions(shift.Work, MergeOptions{Tolerance: tolerance})
+		for _, stretch := range stretches.Ranges {
+			if length := stretch.Range.Duration(); length > r.After {
+				violations = append(violations, ShiftViolation{
+					Rule:    r.Name(),
+					Ranges:  []TimeRange{stretch.Range},
+					Message: fmt.Sprintf("%s without a break of %s exceeds %s", length, r.Break, r.After),
+				})
+			}
+		}
+	}
+	return violations
+}
@@ -0,0 +1,128 @@
+package timerange
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGroupShifts(t *testing.T) {
+	schedule := []TimeRange{
+		mayRange(1, 13, 17),
+		mayRange(1, 9, 12),
+		mayRange(2, 9, 17),
+	}
+
+	shifts := GroupShifts(schedule, 2*time.Hour)
+	if len(shifts) != 2 {
+		t.Fatalf("GroupShifts() returned %d shifts, want 2", len(shifts))
+	}
+	if !shifts[0].Range.Equal(mayRange(1, 9, 17)) || len(shifts[0].Work) != 2 {
+		t.Errorf("GroupShifts()[0] = %+v, want 09:00-17:00 with two work periods", shifts[0])
+	}
+	if shifts[0].Worked() != 7*time.Hour {
+		t.Errorf("Worked() = %v, want 7h", shifts[0].Worked())
+	}
+}
+
+func TestLaborRules(t *testing.T) {
+	rules := LaborRules{
+		MaxBreak: 2 * time.Hour,
+		Rules: []ShiftRule{
+			MinRest{Duration: 11 * time.Hour},
+			MaxShiftLength{Duration: 10 * time.Hour},
+			MaxConsecutiveDays{Days: 5},
+			MandatoryBreak{After: 6 * time.Hour, Break: 30 * time.Minute},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		schedule []TimeRange
+		expected []ShiftViolation
+	}{
+		{
+			name: "compliant schedule",
+			schedule: []TimeRange{
+				mayRange(1, 9, 13), mayRange(1, 14, 18),
+				mayRange(2, 9, 13), mayRange(2, 14, 18),
+			},
+		},
+		{
+			name:     "short rest",
+			schedule: []TimeRange{mayRange(1, 14, 20), mayRange(2, 6, 10)},
+			expected: []ShiftViolation{{
+				Rule:    "min rest",
+				Ranges:  []TimeRange{mayRange(1, 14, 20), mayRange(2, 6, 10)},
+				Message: "rest 10h0m0s is shorter than 11h0m0s",
+			}},
+		},
+		{
+			name:     "long shift with breaks",
+			schedule: []TimeRange{mayRange(1, 6, 10), mayRange(1, 11, 14), mayRange(1, 15, 18)},
+			expected: []ShiftViolation{{
+				Rule:    "max shift length",
+				Ranges:  []TimeRange{mayRange(1, 6, 18)},
+				Message: "shift length 12h0m0s exceeds 10h0m0s",
+			}},
+		},
+		{
+			name: "short pause is not a break",
+			schedule: []TimeRange{
+				{Start: mayAt(1, 9), End: mayAt(1, 12)},
+				{Start: mayAt(1, 12).Add(15 * time.Minute), End: mayAt(1, 16)},
+			},
+			expected: []ShiftViolation{{
+				Rule:    "mandatory break",
+				Ranges:  []TimeRange{mayRange(1, 9, 16)},
+				Message: "7h0m0s without a break of 30m0s exceeds 6h0m0s",
+			}},
+		},
+		{
+			name: "six days in a row",
+			schedule: []TimeRange{
+				mayRange(1, 9, 13), mayRange(2, 9, 13), mayRange(3, 9, 13),
+				mayRange(4, 9, 13), mayRange(5, 9, 13), mayRange(6, 9, 13),
+				mayRange(8, 9, 13),
+			},
+			expected: []ShiftViolation{{
+				Rule: "max consecutive days",
+				Ranges: []TimeRange{
+					mayRange(1, 9, 13), mayRange(2, 9, 13), mayRange(3, 9, 13),
+					mayRange(4, 9, 13), mayRange(5, 9, 13), mayRange(6, 9, 13),
+				},
+				Message: "6 consecutive working days exceed 5",
+			}},
+		},
+		{
+			name: "night shifts count both days",
+			schedule: []TimeRange{
+				{Start: mayAt(1, 22), End: mayAt(2, 4)},
+				{Start: mayAt(2, 22), End: mayAt(3, 4)},
+				{Start: mayAt(3, 22), End: mayAt(4, 4)},
+				{Start: mayAt(4, 22), End: mayAt(5, 4)},
+				{Start: mayAt(5, 22), End: mayAt(6, 4)},
+			},
+			expected: []ShiftViolation{{
+				Rule: "max consecutive days",
+				Ranges: []TimeRange{
+					{Start: mayAt(1, 22), End: mayAt(2, 4)},
+					{Start: mayAt(2, 22), End: mayAt(3, 4)},
+					{Start: mayAt(3, 22), End: mayAt(4, 4)},
+					{Start: mayAt(4, 22), End: mayAt(5, 4)},
+					{Start: mayAt(5, 22), End: mayAt(6, 4)},
+				},
+				Message: "6 consecutive working days exceed 5",
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := rules.Validate(tt.schedule)
+			if !reflect.DeepEqual(violations, tt.expected) {
+				t.Errorf("Validate() = %+v, want %+v", violations, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package timerange
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// MeterRounding задает, к чему применяются минимум и округление.
+type MeterRounding int
+
+const (
+	// RoundPerRange - к каждому непрерывному отрезку использования внутри периода.
+	RoundPerRange MeterRounding = iota
+	// RoundPerPeriod - к суммарному использованию ключа за период.
+	RoundPerPeriod
+)
+
+// Meter собирает интервалы использования по ключам (например, по экземплярам)
+// и считает оплачиваемые единицы. Перекрывающиеся и повторные интервалы
+// одного ключа учитываются один раз. Безопасен для конкурентного использования.
+type Meter struct {
+	Period      time.Duration // длина расчетного периода, 0 - весь запрошенный интервал
+	MinDuration time.Duration // минимальная оплачиваемая длительность
+	Increment   time.Duration // шаг округления вверх, 0 - без округления
+	Unit        time.Duration // единица вывода, по умолчанию Increment или секунда
+	Rounding    MeterRounding
+
+	mu    sync.Mutex
+	usage map[string][]TimeRange
+}
+
+// MeterLine - использование ключа за один период.
+type MeterLine struct {
+	Key      string        `json:"key"`
+	Period   TimeRange     `json:"period"`
+	Used     time.Duration `json:"used"`     // фактическое время без дубликатов
+	Billable time.Duration `json:"billable"` // после минимума и округления
+	Units    int64         `json:"units"`    // Billable в единицах Unit с округлением вверх
+}
+
+func NewMeter(period, increment time.Duration) *Meter {
+	return &Meter{Period: period, Increment: increment}
+}
+
+// Record добавляет интервалы использования ключа. Пустые интервалы пропускаются.
+func (m *Meter) Record(key string, ranges ...TimeRange) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.usage == nil {
+		m.usage = make(map[string][]TimeRange)
+	}
+	merged, _ := MergeOverlapping(append(m.usage[key], ranges...))
+	if merged = dropEmpty(merged); len(merged) > 0 {
+		m.usage[key] = merged
+	}
+}
+
+// Usage возвращает объединенные интервалы использования ключа.
+func (m *Meter) Usage(key string) []TimeRange {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]TimeRange(nil), m.usage[key]...)
+}
+
+// Keys возвращает ключи с записанным использованием в порядке сортировки.
+func (m *Meter) Keys() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]string, 0, len(m.usage))
+	for key := range m.usage {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Bill делит bounds на периоды длиной Period от bounds.Start и возвращает
+// строки по ключам и периодам в порядке сортировки ключей. Отрезок,
+// пересекающий границу периода, разрезается по ней. Периоды без
+// использования пропускаются.
+func (m *Meter) Bill(bounds TimeRange) ([]MeterLine, error) {
+	if m.Period < 0 || m.MinDuration < 0 || m.Increment < 0 || m.Unit < 0 {
+		return nil, ErrInvalidArgument
+	}
+	if !bounds.Start.Before(bounds.End) {
+		return nil, nil
+	}
+	periods := bounds.SplitByDuration(m.Period)
+
+	var lines []MeterLine
+	for _, key := range m.Keys() {
+		usage := m.Usage(key)
+		for _, period := range periods {
+			if line, ok := m.line(key, usage, period); ok {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return lines, nil
+}
+
+// --- Helper Functions ---
+
+func (m *Meter) line(key string, usage []TimeRange, period TimeRange) (MeterLine, bool) {
+	line := MeterLine{Key: key, Period: period}
+	for _, tr := range clipSet(usage, period) {
+		line.Used += tr.Duration()
+		if m.Rounding == RoundPerRange {
+			line.Billable += m.billable(tr.Duration())
+		}
+	}
+	if line.Used == 0 {
+		return MeterLine{}, false
+	}
+	if m.Rounding == RoundPerPeriod {
+		line.Billable = m.billable(line.Used)
+	}
+	line.Units = ceilDiv(line.Billable, m.unit())
+	return line, true
+}
+
+// billable применяет минимум и округляет вверх до Increment.
+func (m *Meter) billable(used time.Duration) time.Duration {
+	used = max(used, m.MinDuration)
+	if m.Increment > 0 {
+		used = time.Duration(ceilDiv(used, m.Increment)) * m.Increment
+	}
+	return used
+}
+
+func (m *Meter) unit() time.Duration {
+	switch {
+	case m.Unit > 0:
+		return m.Unit
+	case m.Increment > 0:
+		return m.Increment
+	default:
+		return time.Second
+	}
+}
+
+func ceilDiv(d, step time.Duration) int64 {
+	return int64((d + step - 1) / step)
+}
@@ -0,0 +1,89 @@
+package timerange
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMeterRecord(t *testing.T) {
+	m := NewMeter(0, 0)
+	m.Record("a", hourRange(1, 3), hourRange(2, 4), hourRange(1, 3))
+	m.Record("a", hourRange(5, 6), hourRange(7, 7))
+	m.Record("b", hourRange(8, 8))
+
+	want := []TimeRange{hourRange(1, 4), hourRange(5, 6)}
+	if got := m.Usage("a"); !compareRanges(got, want) {
+		t.Errorf("Usage() = %v, want %v", got, want)
+	}
+	if got := m.Keys(); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("Keys() = %v, want [a]", got)
+	}
+}
+
+func TestMeterBill(t *testing.T) {
+	minute := func(from, to int) TimeRange {
+		base := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		return TimeRange{Start: base.Add(time.Duration(from) * time.Minute), End: base.Add(time.Duration(to) * time.Minute)}
+	}
+	usage := map[string][]TimeRange{
+		"i-1": {minute(0, 10), minute(5, 20), minute(50, 52), minute(58, 65)},
+		"i-2": {minute(30, 31)},
+	}
+
+	tests := []struct {
+		name  string
+		meter *Meter
+		want  []MeterLine
+	}{
+		{
+			name:  "per range",
+			meter: &Meter{Period: time.Hour, MinDuration: time.Minute * 5, Increment: time.Minute},
+			want: []MeterLine{
+				{Key: "i-1", Period: minute(0, 60), Used: 24 * time.Minute, Billable: 30 * time.Minute, Units: 30},
+				{Key: "i-1", Period: minute(60, 120), Used: 5 * time.Minute, Billable: 5 * time.Minute, Units: 5},
+				{Key: "i-2", Period: minute(0, 60), Used: time.Minute, Billable: 5 * time.Minute, Units: 5},
+			},
+		},
+		{
+			name:  "per period",
+			meter: &Meter{Period: time.Hour, Increment: 15 * time.Minute, Unit: time.Hour, Rounding: RoundPerPeriod},
+			want: []MeterLine{
+				{Key: "i-1", Period: minute(0, 60), Used: 24 * time.Minute, Billable: 30 * time.Minute, Units: 1},
+				{Key: "i-1", Period: minute(60, 120), Used: 5 * time.Minute, Billable: 15 * time.Minute, Units: 1},
+				{Key: "i-2", Period: minute(0, 60), Used: time.Minute, Billable: 15 * time.Minute, Units: 1},
+			},
+		},
+		{
+			name:  "single period",
+			meter: &Meter{Increment: time.Minute * 10},
+			want: []MeterLine{
+				{Key: "i-1", Period: minute(0, 120), Used: 29 * time.Minute, Billable: 40 * time.Minute, Units: 4},
+				{Key: "i-2", Period: minute(0, 120), Used: time.Minute, Billable: 10 * time.Minute, Units: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, ranges := range usage {
+				tt.meter.Record(key, ranges...)
+			}
+			got, err := tt.meter.Bill(minute(0, 120))
+			if err != nil {
+				t.Fatalf("Bill() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Bill() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMeterBillInvalid(t *testing.T) {
+	m := &Meter{Increment: -time.Minute}
+	if _, err := m.Bill(hourRange(0, 1)); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("Bill() error = %v, want ErrInvalidArgument", err)
+	}
+}
@@ -0,0 +1,163 @@
+package timerange
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Policy задает приведение моментов времени к единому виду. Политика
+// применяется явно: через Normalize или методы Policy. Функции пакета
+// (New, операции с множествами, UnmarshalJSON) применяют нулевую Policy,
+// то есть только отбрасывают показания монотонных часов, поэтому их
+// результаты можно сравнивать через ==.
+type Policy struct {
+	Location  *time.Location // nil - зона не меняется
+	Precision time.Duration  // 0 - без усечения
+}
+
+// Time приводит момент t к политике: без монотонных часов, с усечением до
+// Precision и в зоне Location.
+func (p Policy) Time(t time.Time) time.Time {
+	t = t.Round(0)
+	if p.Precision > 0 {
+		t = t.Truncate(p.Precision)
+	}
+	if p.Location != nil {
+		t = t.In(p.Location)
+	}
+	return t
+}
+
+// Range приводит обе границы интервала к политике. Усечение может
+// схлопнуть короткий интервал: [10:10, 10:30) с точностью в час дает
+// [10:00, 10:00). Методы Policy ниже такие интервалы отбрасывают или
+// возвращают ErrInvalidRange.
+func (p Policy) Range(tr TimeRange) TimeRange {
+	return TimeRange{Start: p.Time(tr.Start), End: p.Time(tr.End)}
+}
+
+// New создает интервал по политике. Если непустой интервал схлопывается
+// при усечении, возвращается ErrInvalidRange.
+func (p Policy) New(start, end time.Time) (TimeRange, error) {
+	tr, err := New(start, end)
+	if err != nil {
+		return TimeRange{}, err
+	}
+	return p.strict(tr)
+}
+
+// Unmarshal разбирает интервал из JSON и приводит его к политике.
+// Схлопнувшийся интервал отклоняется, как в New.
+func (p Policy) Unmarshal(data []byte) (TimeRange, error) {
+	var tr TimeRange
+	if err := json.Unmarshal(data, &tr); err != nil {
+		return TimeRange{}, err
+	}
+	return p.strict(tr)
+}
+
+// Ranges приводит интервалы к политике и отбрасывает схлопнувшиеся.
+func (p Policy) Ranges(ranges []TimeRange) []TimeRange {
+	result := make([]TimeRange, 0, len(ranges))
+	for _, tr := range ranges {
+		if normalized := p.Range(tr); !collapsed(tr, normalized) {
+			result = append(result, normalized)
+		}
+	}
+	return result
+}
+
+// Union, Intersection, MergeOverlapping, SubtractAll и FindGaps приводят
+// входные интервалы к политике и выполняют одноименные операции пакета.
+// Границы результата берутся из входных интервалов, поэтому результат
+// тоже соответствует политике.
+func (p Policy) Union(ranges []TimeRange) ([]TimeRange, error) {
+	return Union(p.Ranges(ranges))
+}
+
+// Intersection не отбрасывает схлопнувшиеся интервалы: они сужают
+// пересечение до пустого.
+func (p Policy) Intersection(ranges []TimeRange) (TimeRange, error) {
+	normalized := make([]TimeRange, len(ranges))
+	for i, tr := range ranges {
+		normalized[i] = p.Range(tr)
+	}
+	return Intersection(normalized)
+}
+
+func (p Policy) MergeOverlapping(ranges []TimeRange) ([]TimeRange, error) {
+	return MergeOverlapping(p.Ranges(ranges))
+}
+
+func (p Policy) SubtractAll(tr TimeRange, others []TimeRange) []TimeRange {
+	return p.Range(tr).SubtractAll(p.Ranges(others))
+}
+
+func (p Policy) FindGaps(occupied []TimeRange, bounds TimeRange) ([]TimeRange, error) {
+	return FindGaps(p.Ranges(occupied), p.Range(bounds))
+}
+
+// Normalize возвращает интервал в зоне loc с точностью precision.
+// nil loc оставляет зону, нулевая precision - исходную точность.
+// Как и Policy.Range, может вернуть схлопнувшийся интервал.
+func (tr TimeRange) Normalize(loc *time.Location, precision time.Duration) TimeRange {
+	return Policy{Location: loc, Precision: precision}.Range(tr)
+}
+
+// RangeKey - сравнимое представление интервала, не зависящее от зоны и
+// монотонных часов. Подходит для ключей map.
+type RangeKey struct {
+	start, end instant
+}
+
+type instant struct {
+	sec  int64
+	nsec int32
+}
+
+// Key возвращает ключ, равный для интервалов, совпадающих по Equal.
+func (tr TimeRange) Key() RangeKey {
+	return RangeKey{start: instantOf(tr.Start), end: instantOf(tr.End)}
+}
+
+// Range восстанавливает интервал из ключа в UTC.
+func (k RangeKey) Range() TimeRange {
+	return TimeRange{
+		Start: time.Unix(k.start.sec, int64(k.start.nsec)).UTC(),
+		End:   time.Unix(k.end.sec, int64(k.end.nsec)).UTC(),
+	}
+}
+
+// --- Helper Functions ---
+
+func instantOf(t time.Time) instant {
+	return instant{sec: t.Unix(), nsec: int32(t.Nanosecond())}
+}
+
+// strict приводит tr к политике и отклоняет схлопнувшийся интервал.
+func (p Policy) strict(tr TimeRange) (TimeRange, error) {
+	normalized := p.Range(tr)
+	if collapsed(tr, normalized) {
+		return TimeRange{}, fmt.Errorf("%w: range collapses at precision %v", ErrInvalidRange, p.Precision)
+	}
+	return normalized, nil
+}
+
+// collapsed сообщает, что непустой интервал стал пустым после приведения.
+func collapsed(before, after TimeRange) bool {
+	return before.Start.Before(before.End) && !after.Start.Before(after.End)
+}
+
+// normalized отбрасывает показания монотонных часов.
+func normalized(tr TimeRange) TimeRange {
+	return Policy{}.Range(tr)
+}
+
+// normalizeAll отбрасывает показания монотонных часов на месте.
+func normalizeAll(ranges []TimeRange) []TimeRange {
+	for i := range ranges {
+		ranges[i] = normalized(ranges[i])
+	}
+	return ranges
+}
@@ -0,0 +1,237 @@
+package timerange
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPolicyTime(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	instant := time.Date(2024, 1, 1, 12, 30, 45, 123456789, moscow)
+
+	tests := []struct {
+		name     string
+		policy   Policy
+		expected time.Time
+	}{
+		{"zero policy keeps value", Policy{}, instant},
+		{"precision truncates", Policy{Precision: time.Second}, time.Date(2024, 1, 1, 12, 30, 45, 0, moscow)},
+		{"location converts", Policy{Location: time.UTC}, time.Date(2024, 1, 1, 9, 30, 45, 123456789, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Time(instant); got != tt.expected {
+				t.Errorf("Time() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	t.Run("monotonic reading is stripped", func(t *testing.T) {
+		now := time.Now()
+		if got := (Policy{}).Time(now); got != now.Round(0) {
+			t.Errorf("Time() = %v, want %v", got, now.Round(0))
+		}
+	})
+}
+
+func TestNormalize(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	a := TimeRange{
+		Start: time.Date(2024, 1, 1, 12, 0, 0, 500, moscow),
+		End:   time.Date(2024, 1, 1, 13, 0, 0, 0, moscow),
+	}
+	b := TimeRange{
+		Start: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+	}
+
+	if a == b {
+		t.Fatal("ranges from different zones should differ before normalization")
+	}
+	if got := a.Normalize(time.UTC, time.Millisecond); got != b {
+		t.Errorf("Normalize() = %v, want %v", got, b)
+	}
+}
+
+func TestKey(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	utc := TimeRange{
+		Start: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+	}
+	local := TimeRange{Start: utc.Start.In(moscow), End: utc.End.In(moscow)}
+
+	seen := map[RangeKey]bool{utc.Key(): true}
+	if !seen[local.Key()] {
+		t.Error("Key() should not depend on location")
+	}
+
+	now := time.Now()
+	withMonotonic := TimeRange{Start: now, End: now.Add(time.Hour)}
+	withoutMonotonic := TimeRange{Start: now.Round(0), End: now.Add(time.Hour).Round(0)}
+	if withMonotonic.Key() != withoutMonotonic.Key() {
+		t.Error("Key() should not depend on monotonic clock reading")
+	}
+
+	if got := local.Key().Range(); got != utc {
+		t.Errorf("RangeKey.Range() = %v, want %v", got, utc)
+	}
+
+	other, _ := utc.Shift(time.Nanosecond)
+	if other.Key() == utc.Key() {
+		t.Error("Key() should differ for different ranges")
+	}
+}
+
+func TestPolicyOperations(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	policy := Policy{Location: time.UTC, Precision: time.Second}
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 999, moscow)
+	end := time.Date(2024, 1, 1, 14, 0, 0, 0, moscow)
+	raw := TimeRange{Start: start, End: end}
+	expected := TimeRange{
+		Start: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
+	}
+
+	t.Run("New", func(t *testing.T) {
+		tr, err := policy.New(start, end)
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		if tr != expected {
+			t.Errorf("New() = %v, want %v", tr, expected)
+		}
+	})
+
+	t.Run("set operations", func(t *testing.T) {
+		merged, _ := policy.MergeOverlapping([]TimeRange{raw})
+		union, _ := policy.Union([]TimeRange{raw})
+		intersection, _ := policy.Intersection([]TimeRange{raw})
+		if merged[0] != expected || union[0] != expected || intersection != expected {
+			t.Errorf("results = %v, %v, %v, want %v", merged[0], union[0], intersection, expected)
+		}
+	})
+
+	t.Run("gaps and subtraction", func(t *testing.T) {
+		busy := TimeRange{Start: start.Add(30 * time.Minute), End: start.Add(time.Hour)}
+		want := []TimeRange{
+			{Start: expected.Start, End: expected.Start.Add(30 * time.Minute)},
+			{Start: expected.Start.Add(time.Hour), End: expected.End},
+		}
+
+		gaps, _ := policy.FindGaps([]TimeRange{busy}, raw)
+		subtracted := policy.SubtractAll(raw, []TimeRange{busy})
+		for i := range want {
+			if len(gaps) != len(want) || gaps[i] != want[i] {
+				t.Fatalf("FindGaps() = %v, want %v", gaps, want)
+			}
+			if len(subtracted) != len(want) || subtracted[i] != want[i] {
+				t.Fatalf("SubtractAll() = %v, want %v", subtracted, want)
+			}
+		}
+	})
+
+	t.Run("Unmarshal", func(t *testing.T) {
+		data := []byte(`{"start":"2024-01-01T12:00:00.5+03:00","end":"2024-01-01T14:00:00+03:00"}`)
+		tr, err := policy.Unmarshal(data)
+		if err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		if tr != expected {
+			t.Errorf("Unmarshal() = %v, want %v", tr, expected)
+		}
+	})
+}
+
+func TestPolicyCollapse(t *testing.T) {
+	hourly := Policy{Precision: time.Hour}
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+	short := TimeRange{Start: at(10, 10), End: at(10, 30)}
+	long := TimeRange{Start: at(9, 30), End: at(12, 15)}
+
+	t.Run("Range keeps collapsed range", func(t *testing.T) {
+		if got := hourly.Range(short); got.Duration() != 0 || !got.Start.Equal(at(10, 0)) {
+			t.Errorf("Range() = %v, want empty range at 10:00", got)
+		}
+	})
+
+	t.Run("New rejects collapsed range", func(t *testing.T) {
+		if _, err := hourly.New(short.Start, short.End); !errors.Is(err, ErrInvalidRange) {
+			t.Errorf("New() error = %v, want ErrInvalidRange", err)
+		}
+		// Пустой интервал не схлопывается
+		if _, err := hourly.New(short.Start, short.Start); err != nil {
+			t.Errorf("New() error = %v for empty range", err)
+		}
+	})
+
+	t.Run("Unmarshal rejects collapsed range", func(t *testing.T) {
+		data := []byte(`{"start":"2024-01-01T10:10:00Z","end":"2024-01-01T10:30:00Z"}`)
+		if _, err := hourly.Unmarshal(data); !errors.Is(err, ErrInvalidRange) {
+			t.Errorf("Unmarshal() error = %v, want ErrInvalidRange", err)
+		}
+	})
+
+	t.Run("collapsed ranges are dropped", func(t *testing.T) {
+		want := []TimeRange{{Start: at(9, 0), End: at(12, 0)}}
+		if got := hourly.Ranges([]TimeRange{short, long}); !compareRanges(got, want) {
+			t.Errorf("Ranges() = %v, want %v", got, want)
+		}
+		if got, _ := hourly.Union([]TimeRange{short, long}); !compareRanges(got, want) {
+			t.Errorf("Union() = %v, want %v", got, want)
+		}
+		if got, _ := hourly.FindGaps([]TimeRange{short}, long); !compareRanges(got, want) {
+			t.Errorf("FindGaps() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("collapsed range empties intersection", func(t *testing.T) {
+		got, err := hourly.Intersection([]TimeRange{short, long})
+		if err != nil || got.Duration() != 0 {
+			t.Errorf("Intersection() = %v, %v, want empty range", got, err)
+		}
+	})
+}
+
+func TestMonotonicReadingsStripped(t *testing.T) {
+	now := time.Now()
+	hour := TimeRange{Start: now, End: now.Add(time.Hour)}
+	later := TimeRange{Start: now.Add(2 * time.Hour), End: now.Add(3 * time.Hour)}
+	strip := func(tr TimeRange) TimeRange {
+		return TimeRange{Start: tr.Start.Round(0), End: tr.End.Round(0)}
+	}
+
+	created, _ := New(hour.Start, hour.End)
+	merged, _ := hour.Merge(TimeRange{Start: hour.End, End: later.Start})
+	union, _ := Union([]TimeRange{hour, later})
+	intersection, _ := Intersection([]TimeRange{hour})
+	added, _ := Diff(nil, []TimeRange{hour})
+	result, _ := MergeWithOptions([]TimeRange{hour}, MergeOptions{})
+
+	tests := []struct {
+		name string
+		got  TimeRange
+		want TimeRange
+	}{
+		{"New", created, strip(hour)},
+		{"Merge", merged, TimeRange{Start: now.Round(0), End: later.Start.Round(0)}},
+		{"Gap", hour.Gap(later), TimeRange{Start: hour.End.Round(0), End: later.Start.Round(0)}},
+		{"SplitByDuration", hour.SplitByDuration(time.Hour)[0], strip(hour)},
+		{"Union", union[1], strip(later)},
+		{"Intersection", intersection, strip(hour)},
+		{"Subtract", hour.Subtract(later)[0], strip(hour)},
+		{"Diff", added[0], strip(hour)},
+		{"MergeWithOptions", result.Ranges[0].Range, strip(hour)},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s() = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
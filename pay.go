@@ -0,0 +1,102 @@
+package timerange
+
+import (
+	"slices"
+	"sort"
+	"time"
+)
+
+// PayRule - правило оплаты: надбавка действует на пересечении смены с окнами
+// Source, начиная с After отработанного времени от начала смены.
+// Пустой Source означает всю смену - так задаются сверхурочные.
+type PayRule struct {
+	Label    string
+	Source   Source
+	After    time.Duration
+	Priority int // большее значение - выше приоритет
+	// Exclusive отменяет правила с меньшим приоритетом на том же отрезке,
+	// например праздничная ставка вместо выходной.
+	Exclusive bool
+}
+
+// PaySegment - отрезок смены с действующими правилами в порядке приоритета.
+// Отрезки без правил имеют пустой Labels.
+type PaySegment struct {
+	Range  TimeRange `json:"range"`
+	Labels []string  `json:"labels"`
+}
+
+// PaySplit - разбиение смены по правилам оплаты.
+type PaySplit struct {
+	Segments  []PaySegment             `json:"segments"`
+	Totals    map[string]time.Duration `json:"totals"`    // время под каждой меткой
+	Unlabeled time.Duration            `json:"unlabeled"` // время без надбавок
+}
+
+// SplitByRules режет смену tr по окнам правил и возвращает отрезки с
+// метками и суммарные длительности. Правила могут пересекаться; отрезок
+// с несколькими правилами учитывается в Totals под каждой меткой.
+func SplitByRules(tr TimeRange, rules []PayRule) PaySplit {
+	split := PaySplit{Totals: make(map[string]time.Duration)}
+	if !tr.Start.Before(tr.End) {
+		return split
+	}
+
+	intervals := []Interval[[]int]{{TimeRange: tr}}
+	for i, rule := range rules {
+		active := TimeRange{Start: minTime(tr.Start.Add(max(rule.After, 0)), tr.End), End: tr.End}
+		if !active.Start.Before(active.End) {
+			continue
+		}
+
+		windows := []TimeRange{active}
+		if rule.Source != nil {
+			windows = clipSet(rule.Source.Ranges(active), active)
+		}
+		for _, window := range windows {
+			intervals = append(intervals, Interval[[]int]{TimeRange: window, Value: []int{i}})
+		}
+	}
+
+	concat := func(a, b []int) []int {
+		return append(slices.Clone(a), b...)
+	}
+	var labeled []Interval[[]string]
+	for _, segment := range Merge(intervals, concat) {
+		labeled = append(labeled, Interval[[]string]{
+			TimeRange: segment.TimeRange,
+			Value:     payLabels(rules, segment.Value),
+		})
+	}
+
+	for _, segment := range CoalesceFunc(labeled, slices.Equal[[]string]) {
+		split.Segments = append(split.Segments, PaySegment{Range: segment.TimeRange, Labels: segment.Value})
+		if len(segment.Value) == 0 {
+			split.Unlabeled += segment.Duration()
+		}
+		for _, label := range segment.Value {
+			split.Totals[label] += segment.Duration()
+		}
+	}
+	return split
+}
+
+// payLabels упорядочивает правила отрезка по приоритету и отбрасывает
+// перекрытые исключающими правилами.
+func payLabels(rules []PayRule, indexes []int) []string {
+	sorted := slices.Clone(indexes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return rules[sorted[i]].Priority > rules[sorted[j]].Priority
+	})
+
+	var labels []string
+	for _, i := range sorted {
+		if !slices.Contains(labels, rules[i].Label) {
+			labels = append(labels, rules[i].Label)
+		}
+		if rules[i].Exclusive {
+			break
+		}
+	}
+	return labels
+}
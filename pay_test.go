@@ -0,0 +1,70 @@
+package timerange
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSplitByRules(t *testing.T) {
+	night, err := CompileExpr("22:00-06:00", ExprOptions{})
+	if err != nil {
+		t.Fatalf("CompileExpr() error = %v", err)
+	}
+	rules := []PayRule{
+		{Label: "night", Source: night, Priority: 1},
+		{Label: "weekend", Source: WeeklyWindow(time.Saturday, 0, time.Monday, 0, time.UTC), Priority: 2},
+		{Label: "holiday", Source: FixedRanges(TimeRange{Start: mayAt(12, 0), End: mayAt(13, 0)}), Priority: 3, Exclusive: true},
+		{Label: "overtime", After: 6 * time.Hour, Priority: 4},
+	}
+
+	t.Run("saturday evening into sunday", func(t *testing.T) {
+		// 2024-05-11 - суббота, 2024-05-12 - праздничное воскресенье
+		split := SplitByRules(TimeRange{Start: mayAt(11, 20), End: mayAt(12, 4)}, rules)
+
+		expected := []PaySegment{
+			{Range: TimeRange{Start: mayAt(11, 20), End: mayAt(11, 22)}, Labels: []string{"weekend"}},
+			{Range: TimeRange{Start: mayAt(11, 22), End: mayAt(12, 0)}, Labels: []string{"weekend", "night"}},
+			{Range: TimeRange{Start: mayAt(12, 0), End: mayAt(12, 2)}, Labels: []string{"holiday"}},
+			{Range: TimeRange{Start: mayAt(12, 2), End: mayAt(12, 4)}, Labels: []string{"overtime", "holiday"}},
+		}
+		if !reflect.DeepEqual(split.Segments, expected) {
+			t.Errorf("SplitByRules() segments = %v, want %v", split.Segments, expected)
+		}
+
+		expectedTotals := map[string]time.Duration{
+			"weekend":  4 * time.Hour,
+			"night":    2 * time.Hour,
+			"holiday":  4 * time.Hour,
+			"overtime": 2 * time.Hour,
+		}
+		if !reflect.DeepEqual(split.Totals, expectedTotals) {
+			t.Errorf("SplitByRules() totals = %v, want %v", split.Totals, expectedTotals)
+		}
+		if split.Unlabeled != 0 {
+			t.Errorf("SplitByRules() unlabeled = %v, want 0", split.Unlabeled)
+		}
+	})
+
+	t.Run("regular weekday", func(t *testing.T) {
+		split := SplitByRules(TimeRange{Start: mayAt(8, 9), End: mayAt(8, 17)}, rules)
+
+		expected := []PaySegment{
+			{Range: TimeRange{Start: mayAt(8, 9), End: mayAt(8, 15)}, Labels: nil},
+			{Range: TimeRange{Start: mayAt(8, 15), End: mayAt(8, 17)}, Labels: []string{"overtime"}},
+		}
+		if !reflect.DeepEqual(split.Segments, expected) {
+			t.Errorf("SplitByRules() segments = %v, want %v", split.Segments, expected)
+		}
+		if split.Unlabeled != 6*time.Hour || split.Totals["overtime"] != 2*time.Hour {
+			t.Errorf("SplitByRules() = unlabeled %v, overtime %v, want 6h and 2h", split.Unlabeled, split.Totals["overtime"])
+		}
+	})
+
+	t.Run("empty range", func(t *testing.T) {
+		split := SplitByRules(TimeRange{Start: mayAt(8, 9), End: mayAt(8, 9)}, rules)
+		if len(split.Segments) != 0 {
+			t.Errorf("SplitByRules() segments = %v, want none", split.Segments)
+		}
+	})
+}
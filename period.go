@@ -0,0 +1,278 @@
+package timerange
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidPeriod = errors.New("invalid calendar period")
+
+// CalendarPeriod - календарный период, который можно развернуть в TimeRange.
+// Range разворачивает период в зоне loc (nil - UTC).
+type CalendarPeriod interface {
+	Range(loc *time.Location) TimeRange
+	Contains(t time.Time) bool
+	String() string
+}
+
+// ISOWeek - неделя по ISO 8601, например 2024-W07.
+type ISOWeek struct {
+	Year int
+	Week int
+}
+
+// Month - календарный месяц, например 2024-03.
+type Month struct {
+	Year  int
+	Month time.Month
+}
+
+// Quarter - календарный квартал, например 2024-Q1.
+type Quarter struct {
+	Year    int
+	Quarter int
+}
+
+// Year - календарный год, например 2024.
+type Year int
+
+// --- Constructors ---
+
+// ISOWeekOf, MonthOf, QuarterOf и YearOf берут дату t в ее собственной зоне.
+func ISOWeekOf(t time.Time) ISOWeek {
+	year, week := t.ISOWeek()
+	return ISOWeek{Year: year, Week: week}
+}
+
+func MonthOf(t time.Time) Month {
+	return Month{Year: t.Year(), Month: t.Month()}
+}
+
+func QuarterOf(t time.Time) Quarter {
+	return Quarter{Year: t.Year(), Quarter: (int(t.Month())-1)/3 + 1}
+}
+
+func YearOf(t time.Time) Year {
+	return Year(t.Year())
+}
+
+func ISOWeeksInYear(year int) int {
+	_, week := time.Date(year, time.December, 28, 0, 0, 0, 0, time.UTC).ISOWeek()
+	return week
+}
+
+// --- ISOWeek ---
+
+func (w ISOWeek) Range(loc *time.Location) TimeRange {
+	jan4 := time.Date(w.Year, time.January, 4, 0, 0, 0, 0, orUTC(loc))
+	monday := jan4.AddDate(0, 0, -((int(jan4.Weekday())+6)%7)+7*(w.Week-1))
+	return TimeRange{Start: monday, End: monday.AddDate(0, 0, 7)}
+}
+
+func (w ISOWeek) Contains(t time.Time) bool {
+	return ISOWeekOf(t) == w
+}
+
+func (w ISOWeek) Next() ISOWeek {
+	if w.Week >= ISOWeeksInYear(w.Year) {
+		return ISOWeek{Year: w.Year + 1, Week: 1}
+	}
+	return ISOWeek{Year: w.Year, Week: w.Week + 1}
+}
+
+func (w ISOWeek) Prev() ISOWeek {
+	if w.Week <= 1 {
+		return ISOWeek{Year: w.Year - 1, Week: ISOWeeksInYear(w.Year - 1)}
+	}
+	return ISOWeek{Year: w.Year, Week: w.Week - 1}
+}
+
+func (w ISOWeek) String() string {
+	return fmt.Sprintf("%04d-W%02d", w.Year, w.Week)
+}
+
+func ParseISOWeek(s string) (ISOWeek, error) {
+	yearPart, weekPart, ok := strings.Cut(s, "-W")
+	if !ok || len(yearPart) != 4 || len(weekPart) != 2 {
+		return ISOWeek{}, fmt.Errorf("%w: %q", ErrInvalidPeriod, s)
+	}
+	year, err1 := strconv.Atoi(yearPart)
+	week, err2 := strconv.Atoi(weekPart)
+	if err1 != nil || err2 != nil || week < 1 || week > ISOWeeksInYear(year) {
+		return ISOWeek{}, fmt.Errorf("%w: %q", ErrInvalidPeriod, s)
+	}
+	return ISOWeek{Year: year, Week: week}, nil
+}
+
+// --- Month ---
+
+func (m Month) Range(loc *time.Location) TimeRange {
+	start := time.Date(m.Year, m.Month, 1, 0, 0, 0, 0, orUTC(loc))
+	return TimeRange{Start: start, End: start.AddDate(0, 1, 0)}
+}
+
+func (m Month) Contains(t time.Time) bool {
+	return MonthOf(t) == m
+}
+
+func (m Month) Next() Month {
+	return MonthOf(time.Date(m.Year, m.Month+1, 1, 0, 0, 0, 0, time.UTC))
+}
+
+func (m Month) Prev() Month {
+	return MonthOf(time.Date(m.Year, m.Month-1, 1, 0, 0, 0, 0, time.UTC))
+}
+
+func (m Month) String() string {
+	return fmt.Sprintf("%04d-%02d", m.Year, int(m.Month))
+}
+
+func ParseMonth(s string) (Month, error) {
+	t, err := time.Parse("2006-01", s)
+	if err != nil {
+		return Month{}, fmt.Errorf("%w: %q", ErrInvalidPeriod, s)
+	}
+	return MonthOf(t), nil
+}
+
+// --- Quarter ---
+
+func (q Quarter) Range(loc *time.Location) TimeRange {
+	start := time.Date(q.Year, time.Month(3*(q.Quarter-1)+1), 1, 0, 0, 0, 0, orUTC(loc))
+	return TimeRange{Start: start, End: start.AddDate(0, 3, 0)}
+}
+
+func (q Quarter) Contains(t time.Time) bool {
+	return QuarterOf(t) == q
+}
+
+func (q Quarter) Next() Quarter {
+	if q.Quarter >= 4 {
+		return Quarter{Year: q.Year + 1, Quarter: 1}
+	}
+	return Quarter{Year: q.Year, Quarter: q.Quarter + 1}
+}
+
+func (q Quarter) Prev() Quarter {
+	if q.Quarter <= 1 {
+		return Quarter{Year: q.Year - 1, Quarter: 4}
+	}
+	return Quarter{Year: q.Year, Quarter: q.Quarter - 1}
+}
+
+func (q Quarter) String() string {
+	return fmt.Sprintf("%04d-Q%d", q.Year, q.Quarter)
+}
+
+func ParseQuarter(s string) (Quarter, error) {
+	yearPart, quarterPart, ok := strings.Cut(s, "-Q")
+	if !ok || len(yearPart) != 4 || len(quarterPart) != 1 {
+		return Quarter{}, fmt.Errorf("%w: %q", ErrInvalidPeriod, s)
+	}
+	year, err1 := strconv.Atoi(yearPart)
+	quarter, err2 := strconv.Atoi(quarterPart)
+	if err1 != nil || err2 != nil || quarter < 1 || quarter > 4 {
+		return Quarter{}, fmt.Errorf("%w: %q", ErrInvalidPeriod, s)
+	}
+	return Quarter{Year: year, Quarter: quarter}, nil
+}
+
+// --- Year ---
+
+func (y Year) Range(loc *time.Location) TimeRange {
+	start := time.Date(int(y), time.January, 1, 0, 0, 0, 0, orUTC(loc))
+	return TimeRange{Start: start, End: start.AddDate(1, 0, 0)}
+}
+
+func (y Year) Contains(t time.Time) bool {
+	return YearOf(t) == y
+}
+
+func (y Year) Next() Year {
+	return y + 1
+}
+
+func (y Year) Prev() Year {
+	return y - 1
+}
+
+func (y Year) String() string {
+	return fmt.Sprintf("%04d", int(y))
+}
+
+func ParseYear(s string) (Year, error) {
+	year, err := strconv.Atoi(s)
+	if err != nil || len(s) != 4 || year < 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidPeriod, s)
+	}
+	return Year(year), nil
+}
+
+// ParsePeriod распознает любую из канонических форм: 2024-W07, 2024-Q1, 2024-03, 2024.
+func ParsePeriod(s string) (CalendarPeriod, error) {
+	switch {
+	case strings.Contains(s, "-W"):
+		return ParseISOWeek(s)
+	case strings.Contains(s, "-Q"):
+		return ParseQuarter(s)
+	case strings.Contains(s, "-"):
+		return ParseMonth(s)
+	default:
+		return ParseYear(s)
+	}
+}
+
+// --- Periods Touched by a Range ---
+
+// ISOWeeks, Months, Quarters и Years возвращают периоды в зоне loc, которые
+// пересекает интервал. Конец интервала не включается; при nil loc
+// используется зона tr.Start.
+func (tr TimeRange) ISOWeeks(loc *time.Location) []ISOWeek {
+	loc = rangeLocation(tr, loc)
+	return touchedPeriods(tr, loc, ISOWeekOf(tr.Start.In(loc)))
+}
+
+func (tr TimeRange) Months(loc *time.Location) []Month {
+	loc = rangeLocation(tr, loc)
+	return touchedPeriods(tr, loc, MonthOf(tr.Start.In(loc)))
+}
+
+func (tr TimeRange) Quarters(loc *time.Location) []Quarter {
+	loc = rangeLocation(tr, loc)
+	return touchedPeriods(tr, loc, QuarterOf(tr.Start.In(loc)))
+}
+
+func (tr TimeRange) Years(loc *time.Location) []Year {
+	loc = rangeLocation(tr, loc)
+	return touchedPeriods(tr, loc, YearOf(tr.Start.In(loc)))
+}
+
+type navigablePeriod[P any] interface {
+	Range(loc *time.Location) TimeRange
+	Next() P
+}
+
+func touchedPeriods[P navigablePeriod[P]](tr TimeRange, loc *time.Location, first P) []P {
+	periods := []P{first}
+	for p := first.Next(); p.Range(loc).Start.Before(tr.End); p = p.Next() {
+		periods = append(periods, p)
+	}
+	return periods
+}
+
+func rangeLocation(tr TimeRange, loc *time.Location) *time.Location {
+	if loc == nil {
+		return tr.Start.Location()
+	}
+	return loc
+}
+
+func orUTC(loc *time.Location) *time.Location {
+	if loc == nil {
+		return time.UTC
+	}
+	return loc
+}
@@ -0,0 +1,148 @@
+package timerange
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect CalendarPeriod
+	}{
+		{input: "2024-W07", expect: ISOWeek{Year: 2024, Week: 7}},
+		{input: "2020-W53", expect: ISOWeek{Year: 2020, Week: 53}},
+		{input: "2024-Q1", expect: Quarter{Year: 2024, Quarter: 1}},
+		{input: "2024-03", expect: Month{Year: 2024, Month: time.March}},
+		{input: "2024", expect: Year(2024)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParsePeriod(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.expect {
+				t.Errorf("ParsePeriod() = %v, want %v", got, tt.expect)
+			}
+			if got.String() != tt.input {
+				t.Errorf("String() = %q, want %q", got.String(), tt.input)
+			}
+		})
+	}
+
+	for _, input := range []string{"2024-W54", "2023-W53", "2024-Q5", "2024-13", "24", "2024-W7"} {
+		t.Run("invalid "+input, func(t *testing.T) {
+			if _, err := ParsePeriod(input); !errors.Is(err, ErrInvalidPeriod) {
+				t.Errorf("ParsePeriod(%q) error = %v, want ErrInvalidPeriod", input, err)
+			}
+		})
+	}
+}
+
+func TestPeriodRanges(t *testing.T) {
+	tests := []struct {
+		name   string
+		period CalendarPeriod
+		expect TimeRange
+	}{
+		{
+			name:   "iso week",
+			period: ISOWeek{Year: 2024, Week: 7},
+			expect: TimeRange{
+				Start: time.Date(2024, 2, 12, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2024, 2, 19, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:   "iso week 1 starts in previous year",
+			period: ISOWeek{Year: 2025, Week: 1},
+			expect: TimeRange{
+				Start: time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:   "quarter",
+			period: Quarter{Year: 2024, Quarter: 4},
+			expect: TimeRange{
+				Start: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:   "month",
+			period: Month{Year: 2024, Month: time.February},
+			expect: TimeRange{
+				Start: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:   "year",
+			period: Year(2024),
+			expect: TimeRange{
+				Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.period.Range(time.UTC)
+			if !got.Equal(tt.expect) {
+				t.Errorf("Range() = %v, want %v", got, tt.expect)
+			}
+			if !tt.period.Contains(got.Start) || tt.period.Contains(got.End) {
+				t.Errorf("Contains() must include start and exclude end of %v", got)
+			}
+			// nil - UTC
+			if got := tt.period.Range(nil); !got.Equal(tt.expect) {
+				t.Errorf("Range(nil) = %v, want %v", got, tt.expect)
+			}
+		})
+	}
+}
+
+func TestPeriodNavigation(t *testing.T) {
+	if got := (ISOWeek{Year: 2020, Week: 53}).Next(); got != (ISOWeek{Year: 2021, Week: 1}) {
+		t.Errorf("Next() = %v, want 2021-W01", got)
+	}
+	if got := (ISOWeek{Year: 2021, Week: 1}).Prev(); got != (ISOWeek{Year: 2020, Week: 53}) {
+		t.Errorf("Prev() = %v, want 2020-W53", got)
+	}
+	if got := (Month{Year: 2024, Month: time.December}).Next(); got != (Month{Year: 2025, Month: time.January}) {
+		t.Errorf("Next() = %v, want 2025-01", got)
+	}
+	if got := (Quarter{Year: 2024, Quarter: 1}).Prev(); got != (Quarter{Year: 2023, Quarter: 4}) {
+		t.Errorf("Prev() = %v, want 2023-Q4", got)
+	}
+}
+
+func TestTouchedPeriods(t *testing.T) {
+	tr := TimeRange{
+		Start: time.Date(2024, 1, 30, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	months := tr.Months(time.UTC)
+	expected := []Month{{2024, time.January}, {2024, time.February}, {2024, time.March}}
+	if len(months) != len(expected) {
+		t.Fatalf("Months() = %v, want %v", months, expected)
+	}
+	for i := range expected {
+		if months[i] != expected[i] {
+			t.Errorf("Months()[%d] = %v, want %v", i, months[i], expected[i])
+		}
+	}
+
+	if quarters := tr.Quarters(time.UTC); len(quarters) != 1 {
+		t.Errorf("Quarters() = %v, want [2024-Q1]", quarters)
+	}
+	if weeks := tr.ISOWeeks(time.UTC); len(weeks) != 9 || weeks[0] != (ISOWeek{2024, 5}) {
+		t.Errorf("ISOWeeks() = %v, want 9 weeks from 2024-W05", weeks)
+	}
+}
@@ -0,0 +1,283 @@
+package timerange
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// BillingPeriods - схема расчетных периодов. Без Anchor периоды совпадают с
+// календарными единицами Unit в зоне Location; с Anchor они отсчитываются от
+// даты якоря с прижатием к концу месяца: подписка с 31 января продлевается
+// 29 февраля, затем 31 марта. Нулевой WeekStart - воскресенье, поэтому для
+// недель удобнее CalendarPeriods.
+type BillingPeriods struct {
+	Unit      CalendarUnit
+	Anchor    time.Time
+	Location  *time.Location // по умолчанию UTC
+	WeekStart time.Weekday   // первый день недели для UnitWeek без якоря
+}
+
+// CalendarPeriods - календарные расчетные периоды в зоне loc; недели
+// начинаются с понедельника, как в SplitByCalendar.
+func CalendarPeriods(unit CalendarUnit, loc *time.Location) BillingPeriods {
+	return BillingPeriods{Unit: unit, Location: loc, WeekStart: time.Monday}
+}
+
+// AnchoredPeriods - периоды unit, отсчитываемые от даты anchor.
+func AnchoredPeriods(unit CalendarUnit, anchor time.Time, loc *time.Location) BillingPeriods {
+	return BillingPeriods{Unit: unit, Anchor: anchor, Location: loc, WeekStart: time.Monday}
+}
+
+// ProrationBasis - способ измерения частичного периода.
+type ProrationBasis int
+
+const (
+	// ProrateBySecond - по фактической длительности.
+	ProrateBySecond ProrationBasis = iota
+	// ProrateByDay - по календарным дням: день начала учитывается, день конца нет.
+	ProrateByDay
+	// Prorate30360 - по соглашению 30E/360: каждый месяц считается за 30 дней.
+	Prorate30360
+)
+
+func (b ProrationBasis) String() string {
+	switch b {
+	case ProrateBySecond:
+		return "seconds"
+	case ProrateByDay:
+		return "days"
+	case Prorate30360:
+		return "30/360 days"
+	default:
+		return "unknown"
+	}
+}
+
+// RoundingMode - правило округления сумм до минимальных единиц валюты.
+type RoundingMode int
+
+const (
+	RoundHalfEven RoundingMode = iota // банковское округление
+	RoundHalfUp                       // половина от нуля
+	RoundDown                         // к нулю
+	RoundUp                           // от нуля
+)
+
+// Allocation - часть начисления, приходящаяся на один расчетный период.
+type Allocation struct {
+	Period   TimeRange `json:"period"`
+	Service  TimeRange `json:"service"`  // часть услуги внутри периода
+	Fraction *big.Rat  `json:"fraction"` // доля периода, покрытая услугой
+	Share    *big.Rat  `json:"share"`    // доля в общей сумме до округления
+	Amount   int64     `json:"amount"`   // в минимальных единицах валюты
+}
+
+// Periods возвращает расчетные периоды, пересекающиеся с tr, по порядку.
+func (b BillingPeriods) Periods(tr TimeRange) ([]TimeRange, error) {
+	if !b.Unit.valid() {
+		return nil, ErrInvalidArgument
+	}
+	if !tr.Start.Before(tr.End) {
+		return nil, nil
+	}
+
+	var periods []TimeRange
+	for k := b.index(tr.Start); ; k++ {
+		period := TimeRange{Start: b.boundary(k), End: b.boundary(k + 1)}
+		if !period.Start.Before(tr.End) {
+			return periods, nil
+		}
+		periods = append(periods, period)
+	}
+}
+
+// Prorate начисляет price за каждый период, пропорционально покрытой услугой
+// доле. Каждый период округляется отдельно, поэтому полный период стоит
+// ровно price, а сумма частей может отличаться от точной суммы на ошибки
+// округления. Чтобы части в сумме давали заданное значение, используйте Allocate.
+func Prorate(service TimeRange, periods BillingPeriods, basis ProrationBasis, price int64, mode RoundingMode) ([]Allocation, error) {
+	allocations, _, err := prepareAllocations(service, periods, basis)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := new(big.Rat)
+	for _, a := range allocations {
+		sum.Add(sum, a.Fraction)
+	}
+	for i := range allocations {
+		a := &allocations[i]
+		a.Share = new(big.Rat)
+		if sum.Sign() != 0 {
+			a.Share.Quo(a.Fraction, sum)
+		}
+		exact := new(big.Rat).Mul(a.Fraction, new(big.Rat).SetInt64(price))
+		a.Amount = roundRat(exact, mode).Int64()
+	}
+	return allocations, nil
+}
+
+// Allocate делит total между периодами пропорционально покрытым единицам
+// basis (секундам или дням). Сумма частей всегда равна total.
+func Allocate(service TimeRange, periods BillingPeriods, basis ProrationBasis, total int64, mode RoundingMode) ([]Allocation, error) {
+	allocations, units, err := prepareAllocations(service, periods, basis)
+	if err != nil {
+		return nil, err
+	}
+
+	weights := make([]*big.Rat, len(allocations))
+	sum := new(big.Rat)
+	for i, u := range units {
+		weights[i] = new(big.Rat).SetInt64(u)
+		sum.Add(sum, weights[i])
+	}
+	if sum.Sign() == 0 {
+		return nil, fmt.Errorf("%w: service covers no billable %s", ErrInvalidArgument, basis)
+	}
+	distribute(allocations, weights, new(big.Rat).SetInt64(total), mode)
+	return allocations, nil
+}
+
+// --- Helper Functions ---
+
+// prepareAllocations режет услугу по периодам и возвращает части с долями
+// периодов и покрытые единицы basis.
+func prepareAllocations(service TimeRange, periods BillingPeriods, basis ProrationBasis) ([]Allocation, []int64, error) {
+	if !service.Start.Before(service.End) || basis < ProrateBySecond || basis > Prorate30360 {
+		return nil, nil, ErrInvalidArgument
+	}
+	bounds, err := periods.Periods(service)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	loc := periods.location()
+	allocations := make([]Allocation, len(bounds))
+	units := make([]int64, len(bounds))
+	for i, period := range bounds {
+		part := TimeRange{Start: maxTime(period.Start, service.Start), End: minTime(period.End, service.End)}
+		units[i] = basis.units(part, loc)
+
+		fraction := big.NewRat(1, 1)
+		if !part.Equal(period) {
+			if total := basis.units(period, loc); total > 0 {
+				fraction.SetFrac64(units[i], total)
+			} else {
+				fraction.SetInt64(0)
+			}
+		}
+		allocations[i] = Allocation{Period: period, Service: part, Fraction: fraction}
+	}
+	return allocations, units, nil
+}
+
+// distribute задает доли и суммы частей. Округляются нарастающие итоги,
+// поэтому ошибки округления не накапливаются и части дают в сумме whole.
+func distribute(allocations []Allocation, weights []*big.Rat, whole *big.Rat, mode RoundingMode) {
+	sum := new(big.Rat)
+	for _, w := range weights {
+		sum.Add(sum, w)
+	}
+
+	cumulative := new(big.Rat)
+	prev := new(big.Int)
+	for i := range allocations {
+		if sum.Sign() == 0 {
+			allocations[i].Share = new(big.Rat)
+			continue
+		}
+		allocations[i].Share = new(big.Rat).Quo(weights[i], sum)
+		cumulative.Add(cumulative, weights[i])
+
+		exact := new(big.Rat).Mul(whole, cumulative)
+		exact.Quo(exact, sum)
+		rounded := roundRat(exact, mode)
+		allocations[i].Amount = new(big.Int).Sub(rounded, prev).Int64()
+		prev = rounded
+	}
+}
+
+// roundRat округляет r до целого по правилу mode.
+func roundRat(r *big.Rat, mode RoundingMode) *big.Int {
+	q, m := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if m.Sign() == 0 || mode == RoundDown {
+		return q
+	}
+	step := big.NewInt(int64(r.Sign()))
+	if mode == RoundUp {
+		return q.Add(q, step)
+	}
+
+	twice := new(big.Int).Lsh(m.Abs(m), 1)
+	cmp := twice.Cmp(r.Denom())
+	if cmp > 0 || (cmp == 0 && (mode == RoundHalfUp || q.Bit(0) == 1)) {
+		q.Add(q, step)
+	}
+	return q
+}
+
+// units измеряет интервал в единицах basis в зоне loc.
+func (b ProrationBasis) units(tr TimeRange, loc *time.Location) int64 {
+	switch b {
+	case ProrateByDay:
+		return int64(civilDays(tr.Start.In(loc), tr.End.In(loc)))
+	case Prorate30360:
+		return int64(days360(tr.Start.In(loc), tr.End.In(loc)))
+	default:
+		return int64(tr.Duration())
+	}
+}
+
+// days360 считает дни между датами по соглашению 30E/360.
+func days360(a, b time.Time) int {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	ad, bd = min(ad, 30), min(bd, 30)
+	return 360*(by-ay) + 30*(int(bm)-int(am)) + (bd - ad)
+}
+
+func (b BillingPeriods) location() *time.Location {
+	if b.Location == nil {
+		return time.UTC
+	}
+	return b.Location
+}
+
+// boundary возвращает начало k-го периода. Для якоря граница всегда
+// отсчитывается от самого якоря, чтобы прижатый день не накапливался.
+func (b BillingPeriods) boundary(k int) time.Time {
+	if b.Anchor.IsZero() {
+		return addUnits(startOfUnit(time.Unix(0, 0), b.Unit, b.location(), b.WeekStart), b.Unit, k)
+	}
+	return addUnitsClamped(b.Anchor.In(b.location()), b.Unit, k)
+}
+
+// index возвращает номер периода, содержащего t.
+func (b BillingPeriods) index(t time.Time) int {
+	origin := b.boundary(0)
+	k := int(t.Sub(origin) / approxUnitLength(b.Unit))
+	for b.boundary(k).After(t) {
+		k--
+	}
+	for !b.boundary(k + 1).After(t) {
+		k++
+	}
+	return k
+}
+
+func approxUnitLength(unit CalendarUnit) time.Duration {
+	const day = 24 * time.Hour
+	switch unit {
+	case UnitWeek:
+		return 7 * day
+	case UnitMonth:
+		return 30 * day
+	case UnitQuarter:
+		return 91 * day
+	case UnitYear:
+		return 365 * day
+	default:
+		return day
+	}
+}
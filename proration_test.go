@@ -0,0 +1,282 @@
+package timerange
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func dateRange(fromY int, fromM time.Month, fromD int, toY int, toM time.Month, toD int) TimeRange {
+	return TimeRange{
+		Start: time.Date(fromY, fromM, fromD, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(toY, toM, toD, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestBillingPeriods(t *testing.T) {
+	tests := []struct {
+		name    string
+		periods BillingPeriods
+		tr      TimeRange
+		want    []TimeRange
+	}{
+		{
+			name:    "calendar months",
+			periods: CalendarPeriods(UnitMonth, nil),
+			tr:      dateRange(2023, time.January, 15, 2023, time.March, 10),
+			want: []TimeRange{
+				dateRange(2023, time.January, 1, 2023, time.February, 1),
+				dateRange(2023, time.February, 1, 2023, time.March, 1),
+				dateRange(2023, time.March, 1, 2023, time.April, 1),
+			},
+		},
+		{
+			name:    "anchor on the 31st",
+			periods: AnchoredPeriods(UnitMonth, time.Date(2022, time.October, 31, 0, 0, 0, 0, time.UTC), nil),
+			tr:      dateRange(2023, time.February, 1, 2023, time.March, 31),
+			want: []TimeRange{
+				dateRange(2023, time.January, 31, 2023, time.February, 28),
+				dateRange(2023, time.February, 28, 2023, time.March, 31),
+			},
+		},
+		{
+			name:    "anchor after range",
+			periods: AnchoredPeriods(UnitWeek, time.Date(2024, time.January, 3, 0, 0, 0, 0, time.UTC), nil),
+			tr:      dateRange(2023, time.January, 2, 2023, time.January, 5),
+			want:    []TimeRange{dateRange(2022, time.December, 28, 2023, time.January, 4), dateRange(2023, time.January, 4, 2023, time.January, 11)},
+		},
+		{
+			name:    "weeks start on monday",
+			periods: CalendarPeriods(UnitWeek, nil),
+			tr:      dateRange(2024, time.May, 8, 2024, time.May, 14),
+			want: []TimeRange{
+				dateRange(2024, time.May, 6, 2024, time.May, 13),
+				dateRange(2024, time.May, 13, 2024, time.May, 20),
+			},
+		},
+		{
+			name:    "sunday week start",
+			periods: BillingPeriods{Unit: UnitWeek, WeekStart: time.Sunday},
+			tr:      dateRange(2024, time.May, 8, 2024, time.May, 9),
+			want:    []TimeRange{dateRange(2024, time.May, 5, 2024, time.May, 12)},
+		},
+		{
+			name:    "period boundary",
+			periods: BillingPeriods{Unit: UnitDay},
+			tr:      dateRange(2023, time.January, 2, 2023, time.January, 3),
+			want:    []TimeRange{dateRange(2023, time.January, 2, 2023, time.January, 3)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.periods.Periods(tt.tr)
+			if err != nil {
+				t.Fatalf("Periods() error = %v", err)
+			}
+			if !compareRanges(got, tt.want) {
+				t.Errorf("Periods() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBillingPeriodsLocation(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+
+	periods := CalendarPeriods(UnitMonth, moscow)
+	got, err := periods.Periods(TimeRange{
+		Start: time.Date(2023, time.January, 31, 22, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Periods() error = %v", err)
+	}
+	want := time.Date(2023, time.February, 1, 0, 0, 0, 0, moscow)
+	if len(got) != 1 || !got[0].Start.Equal(want) {
+		t.Errorf("Periods() = %v, want start %v", got, want)
+	}
+}
+
+func TestProrate(t *testing.T) {
+	tests := []struct {
+		name      string
+		service   TimeRange
+		basis     ProrationBasis
+		price     int64
+		fractions []*big.Rat
+		amounts   []int64
+	}{
+		{
+			name:      "by day",
+			service:   dateRange(2023, time.January, 11, 2023, time.March, 1),
+			basis:     ProrateByDay,
+			price:     1000,
+			fractions: []*big.Rat{big.NewRat(21, 31), big.NewRat(1, 1)},
+			amounts:   []int64{677, 1000},
+		},
+		{
+			name:      "30/360",
+			service:   dateRange(2023, time.January, 16, 2023, time.February, 16),
+			basis:     Prorate30360,
+			price:     3000,
+			fractions: []*big.Rat{big.NewRat(1, 2), big.NewRat(1, 2)},
+			amounts:   []int64{1500, 1500},
+		},
+		{
+			name: "by second",
+			service: TimeRange{
+				Start: time.Date(2023, time.February, 28, 12, 0, 0, 0, time.UTC),
+				End:   time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC),
+			},
+			basis:     ProrateBySecond,
+			price:     2800,
+			fractions: []*big.Rat{big.NewRat(1, 56)},
+			amounts:   []int64{50},
+		},
+		{
+			name:      "odd price keeps full period",
+			service:   dateRange(2024, time.April, 16, 2024, time.June, 1),
+			basis:     ProrateByDay,
+			price:     3,
+			fractions: []*big.Rat{big.NewRat(1, 2), big.NewRat(1, 1)},
+			amounts:   []int64{2, 3},
+		},
+		{
+			name:      "half of one unit",
+			service:   dateRange(2024, time.April, 16, 2024, time.June, 1),
+			basis:     ProrateByDay,
+			price:     1,
+			fractions: []*big.Rat{big.NewRat(1, 2), big.NewRat(1, 1)},
+			amounts:   []int64{0, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Prorate(tt.service, BillingPeriods{Unit: UnitMonth}, tt.basis, tt.price, RoundHalfEven)
+			if err != nil {
+				t.Fatalf("Prorate() error = %v", err)
+			}
+			if len(got) != len(tt.amounts) {
+				t.Fatalf("Prorate() returned %d allocations, want %d", len(got), len(tt.amounts))
+			}
+			for i, a := range got {
+				if a.Fraction.Cmp(tt.fractions[i]) != 0 {
+					t.Errorf("Prorate()[%d].Fraction = %v, want %v", i, a.Fraction, tt.fractions[i])
+				}
+				if a.Amount != tt.amounts[i] {
+					t.Errorf("Prorate()[%d].Amount = %d, want %d", i, a.Amount, tt.amounts[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	service := dateRange(2023, time.January, 1, 2023, time.January, 4)
+	days := BillingPeriods{Unit: UnitDay}
+
+	tests := []struct {
+		name    string
+		total   int64
+		mode    RoundingMode
+		amounts []int64
+	}{
+		{name: "half even", total: 100, mode: RoundHalfEven, amounts: []int64{33, 34, 33}},
+		{name: "negative", total: -100, mode: RoundHalfEven, amounts: []int64{-33, -34, -33}},
+		{name: "round down", total: 2, mode: RoundDown, amounts: []int64{0, 1, 1}},
+		{name: "round up", total: 2, mode: RoundUp, amounts: []int64{1, 1, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Allocate(service, days, ProrateBySecond, tt.total, tt.mode)
+			if err != nil {
+				t.Fatalf("Allocate() error = %v", err)
+			}
+			for i, a := range got {
+				if a.Amount != tt.amounts[i] {
+					t.Errorf("Allocate()[%d].Amount = %d, want %d", i, a.Amount, tt.amounts[i])
+				}
+				if a.Share.Cmp(big.NewRat(1, 3)) != 0 {
+					t.Errorf("Allocate()[%d].Share = %v, want 1/3", i, a.Share)
+				}
+			}
+		})
+	}
+}
+
+func TestAllocateSumsToTotal(t *testing.T) {
+	service := TimeRange{
+		Start: time.Date(2023, time.January, 17, 5, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, time.March, 3, 19, 0, 0, 0, time.UTC),
+	}
+	for _, basis := range []ProrationBasis{ProrateBySecond, ProrateByDay, Prorate30360} {
+		for _, mode := range []RoundingMode{RoundHalfEven, RoundHalfUp, RoundDown, RoundUp} {
+			for _, total := range []int64{1, 7, 999, 123457} {
+				got, err := Allocate(service, BillingPeriods{Unit: UnitMonth}, basis, total, mode)
+				if err != nil {
+					t.Fatalf("Allocate() error = %v", err)
+				}
+				var sum int64
+				for _, a := range got {
+					sum += a.Amount
+				}
+				if sum != total {
+					t.Errorf("Allocate(%s, %d) sums to %d", basis, total, sum)
+				}
+			}
+		}
+	}
+}
+
+func TestAllocateErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		service TimeRange
+		periods BillingPeriods
+		basis   ProrationBasis
+	}{
+		{name: "empty service", service: hourRange(1, 1), periods: BillingPeriods{Unit: UnitDay}},
+		{name: "invalid unit", service: hourRange(1, 2), periods: BillingPeriods{}},
+		{name: "invalid basis", service: hourRange(1, 2), periods: BillingPeriods{Unit: UnitDay}, basis: ProrationBasis(9)},
+		{name: "no billable days", service: hourRange(1, 2), periods: BillingPeriods{Unit: UnitDay}, basis: ProrateByDay},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Allocate(tt.service, tt.periods, tt.basis, 100, RoundHalfEven); !errors.Is(err, ErrInvalidArgument) {
+				t.Errorf("Allocate() error = %v, want ErrInvalidArgument", err)
+			}
+		})
+	}
+}
+
+func TestRoundRat(t *testing.T) {
+	tests := []struct {
+		r    *big.Rat
+		mode RoundingMode
+		want int64
+	}{
+		{big.NewRat(5, 2), RoundHalfEven, 2},
+		{big.NewRat(7, 2), RoundHalfEven, 4},
+		{big.NewRat(-5, 2), RoundHalfEven, -2},
+		{big.NewRat(5, 2), RoundHalfUp, 3},
+		{big.NewRat(-5, 2), RoundHalfUp, -3},
+		{big.NewRat(7, 3), RoundHalfUp, 2},
+		{big.NewRat(-7, 3), RoundDown, -2},
+		{big.NewRat(-7, 3), RoundUp, -3},
+		{big.NewRat(6, 3), RoundUp, 2},
+	}
+
+	for _, tt := range tests {
+		if got := roundRat(tt.r, tt.mode).Int64(); got != tt.want {
+			t.Errorf("roundRat(%v, %d) = %d, want %d", tt.r, tt.mode, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package timerange
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// Quota - ограничение суммарной занятости. Если Unit задан, окна выровнены
+// по календарю (например, не больше 3 часов в календарный день), иначе
+// проверяется любое скользящее окно длительностью Window.
+type Quota struct {
+	Name      string
+	Limit     time.Duration
+	Window    time.Duration  // для скользящих квот
+	Unit      CalendarUnit   // для календарных квот
+	Location  *time.Location // зона календарных окон, по умолчанию UTC
+	WeekStart time.Weekday   // первый день недели для UnitWeek
+}
+
+// RollingQuota - не больше limit в любом окне длительностью window.
+func RollingQuota(name string, limit, window time.Duration) Quota {
+	return Quota{Name: name, Limit: limit, Window: window}
+}
+
+// CalendarQuota - не больше limit в каждой календарной единице unit.
+func CalendarQuota(name string, limit time.Duration, unit CalendarUnit, loc *time.Location) Quota {
+	return Quota{Name: name, Limit: limit, Unit: unit, Location: loc, WeekStart: time.Monday}
+}
+
+// QuotaError описывает окно, в котором квота превышена, и сопоставляется с ErrQuotaExceeded.
+type QuotaError struct {
+	Quota  string
+	Window TimeRange
+	Used   time.Duration
+	Limit  time.Duration
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("quota %q exceeded in %s: used %s of %s", e.Quota, e.Window.ToISOString(), e.Used, e.Limit)
+}
+
+func (e *QuotaError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
+// QuotaChecker проверяет кандидата против набора квот. Пересекающиеся
+// интервалы учитываются один раз.
+type QuotaChecker struct {
+	Quotas []Quota
+	// Step - точность MaxAllowed, по умолчанию секунда.
+	Step time.Duration
+}
+
+func NewQuotaChecker(quotas ...Quota) *QuotaChecker {
+	return &QuotaChecker{Quotas: quotas}
+}
+
+// Check возвращает *QuotaError для первой нарушенной квоты. Учитываются
+// только окна, которые пересекает candidate: ранее накопленные нарушения
+// не мешают добавлять время в другие окна.
+func (c *QuotaChecker) Check(existing []TimeRange, candidate TimeRange) error {
+	for _, q := range c.Quotas {
+		if q.Limit < 0 || (!q.Unit.valid() && q.Window <= 0) {
+			return ErrInvalidArgument
+		}
+	}
+	if !candidate.Start.Before(candidate.End) {
+		return nil
+	}
+
+	merged, _ := MergeOverlapping(append(append([]TimeRange(nil), existing...), candidate))
+	merged = dropEmpty(merged)
+	for _, q := range c.Quotas {
+		if err := q.check(merged, candidate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MaxAllowed возвращает самый длинный префикс candidate вида [Start, Start+x),
+// x кратно Step, который проходит все квоты. Если проходит весь candidate,
+// он возвращается без изменений; если не проходит ни один шаг, возвращается
+// ошибка Check для минимального шага.
+func (c *QuotaChecker) MaxAllowed(existing []TimeRange, candidate TimeRange) (TimeRange, error) {
+	err := c.Check(existing, candidate)
+	if err == nil || !errors.Is(err, ErrQuotaExceeded) {
+		return candidate, err
+	}
+
+	step := c.Step
+	if step <= 0 {
+		step = time.Second
+	}
+	prefix := func(steps int64) TimeRange {
+		return TimeRange{Start: candidate.Start, End: candidate.Start.Add(time.Duration(steps) * step)}
+	}
+
+	// Занятость в любом окне не убывает с ростом x, поэтому ищем двоичным поиском
+	lo, hi := int64(0), int64(candidate.Duration()/step)
+	for lo < hi {
+		mid := lo + (hi-lo+1)/2
+		if c.Check(existing, prefix(mid)) == nil {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	if lo == 0 {
+		return TimeRange{}, c.Check(existing, prefix(1))
+	}
+	return prefix(lo), nil
+}
+
+// --- Helper Functions ---
+
+func (q Quota) check(merged []TimeRange, candidate TimeRange) error {
+	if q.Unit.valid() {
+		return q.checkCalendar(merged, candidate)
+	}
+	return q.checkRolling(merged, candidate)
+}
+
+func (q Quota) checkCalendar(merged []TimeRange, candidate TimeRange) error {
+	loc := q.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	start := startOfUnit(candidate.Start, q.Unit, loc, q.WeekStart)
+	for ; start.Before(candidate.End); start = addUnits(start, q.Unit, 1) {
+		window := TimeRange{Start: start, End: addUnits(start, q.Unit, 1)}
+		if used := coveredWithin(merged, window); used > q.Limit {
+			return &QuotaError{Quota: q.Name, Window: window, Used: used, Limit: q.Limit}
+		}
+	}
+	return nil
+}
+
+// checkRolling проверяет окна, начинающиеся в начале или заканчивающиеся в
+// конце занятого интервала: максимум занятости достигается на одном из них.
+func (q Quota) checkRolling(merged []TimeRange, candidate TimeRange) error {
+	reach := TimeRange{Start: candidate.Start.Add(-q.Window), End: candidate.End.Add(q.Window)}
+
+	var windows []TimeRange
+	for _, tr := range merged {
+		if !tr.Overlaps(reach) {
+			continue
+		}
+		windows = append(windows,
+			TimeRange{Start: tr.Start, End: tr.Start.Add(q.Window)},
+			TimeRange{Start: tr.End.Add(-q.Window), End: tr.End},
+		)
+	}
+	sort.Slice(windows, func(i, j int) bool {
+		return windows[i].Start.Before(windows[j].Start)
+	})
+
+	for _, window := range windows {
+		if !window.Overlaps(candidate) {
+			continue
+		}
+		if used := coveredWithin(merged, window); used > q.Limit {
+			return &QuotaError{Quota: q.Name, Window: window, Used: used, Limit: q.Limit}
+		}
+	}
+	return nil
+}
+
+// coveredWithin возвращает суммарное пересечение объединенных интервалов с window.
+func coveredWithin(merged []TimeRange, window TimeRange) time.Duration {
+	var used time.Duration
+	for _, tr := range merged {
+		start, end := maxTime(tr.Start, window.Start), minTime(tr.End, window.End)
+		if start.Before(end) {
+			used += end.Sub(start)
+		}
+	}
+	return used
+}
@@ -0,0 +1,125 @@
+package timerange
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQuotaCheckerCalendar(t *testing.T) {
+	checker := NewQuotaChecker(CalendarQuota("daily", 3*time.Hour, UnitDay, time.UTC))
+	existing := []TimeRange{mayRange(1, 9, 11)}
+
+	tests := []struct {
+		name        string
+		candidate   TimeRange
+		expectedErr bool
+		window      TimeRange
+		used        time.Duration
+	}{
+		{"fits the day", mayRange(1, 14, 15), false, TimeRange{}, 0},
+		{"overlap is counted once", mayRange(1, 10, 12), false, TimeRange{}, 0},
+		{"exceeds the day", mayRange(1, 14, 16), true, mayRange(1, 0, 24), 4 * time.Hour},
+		{"another day is independent", mayRange(2, 9, 12), false, TimeRange{}, 0},
+		{"crossing midnight is split", TimeRange{Start: mayAt(1, 23), End: mayAt(2, 2)}, false, TimeRange{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checker.Check(existing, tt.candidate)
+			if !tt.expectedErr {
+				if err != nil {
+					t.Errorf("Check() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var quotaErr *QuotaError
+			if !errors.As(err, &quotaErr) || !errors.Is(err, ErrQuotaExceeded) {
+				t.Fatalf("Check() error = %v, want *QuotaError", err)
+			}
+			if quotaErr.Quota != "daily" || !quotaErr.Window.Equal(tt.window) || quotaErr.Used != tt.used {
+				t.Errorf("Check() = %+v, want window %v used %v", quotaErr, tt.window, tt.used)
+			}
+		})
+	}
+}
+
+func TestQuotaCheckerRolling(t *testing.T) {
+	checker := NewQuotaChecker(RollingQuota("weekly", 40*time.Hour, 7*24*time.Hour))
+
+	// По 8 часов в день с 1 по 5 мая - ровно 40 часов
+	var existing []TimeRange
+	for day := 1; day <= 5; day++ {
+		existing = append(existing, mayRange(day, 9, 17))
+	}
+
+	t.Run("window still full", func(t *testing.T) {
+		err := checker.Check(existing, mayRange(7, 9, 10))
+		var quotaErr *QuotaError
+		if !errors.As(err, &quotaErr) {
+			t.Fatalf("Check() error = %v, want *QuotaError", err)
+		}
+		if quotaErr.Used != 41*time.Hour {
+			t.Errorf("Check() used = %v, want 41h", quotaErr.Used)
+		}
+	})
+
+	t.Run("first day left the window", func(t *testing.T) {
+		if err := checker.Check(existing, mayRange(8, 9, 17)); err != nil {
+			t.Errorf("Check() error = %v, want nil", err)
+		}
+	})
+
+	t.Run("old violation does not block distant candidate", func(t *testing.T) {
+		overloaded := append([]TimeRange{mayRange(1, 0, 24), mayRange(2, 0, 24)}, existing...)
+		if err := checker.Check(overloaded, mayRange(20, 9, 17)); err != nil {
+			t.Errorf("Check() error = %v, want nil", err)
+		}
+	})
+}
+
+func TestQuotaCheckerMaxAllowed(t *testing.T) {
+	checker := NewQuotaChecker(
+		CalendarQuota("daily", 3*time.Hour, UnitDay, time.UTC),
+		RollingQuota("weekly", 10*time.Hour, 7*24*time.Hour),
+	)
+	checker.Step = time.Minute
+	existing := []TimeRange{mayRange(1, 9, 11), mayRange(2, 9, 12), mayRange(3, 9, 12)}
+
+	tests := []struct {
+		name      string
+		candidate TimeRange
+		expected  TimeRange
+	}{
+		{"whole candidate fits", mayRange(1, 14, 15), mayRange(1, 14, 15)},
+		{"truncated by daily limit", mayRange(1, 14, 18), mayRange(1, 14, 15)},
+		{"truncated by weekly limit", mayRange(4, 9, 12), mayRange(4, 9, 11)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := checker.MaxAllowed(existing, tt.candidate)
+			if err != nil {
+				t.Fatalf("MaxAllowed() error = %v", err)
+			}
+			if !result.Equal(tt.expected) {
+				t.Errorf("MaxAllowed() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+
+	t.Run("nothing fits", func(t *testing.T) {
+		result, err := checker.MaxAllowed(existing, mayRange(2, 14, 15))
+		if !errors.Is(err, ErrQuotaExceeded) || !result.IsZero() {
+			t.Errorf("MaxAllowed() = %v, %v, want zero range and ErrQuotaExceeded", result, err)
+		}
+	})
+
+	t.Run("invalid quota", func(t *testing.T) {
+		invalid := NewQuotaChecker(Quota{Name: "broken", Limit: time.Hour})
+		if _, err := invalid.MaxAllowed(nil, mayRange(1, 9, 10)); err != ErrInvalidArgument {
+			t.Errorf("MaxAllowed() error = %v, want ErrInvalidArgument", err)
+		}
+	})
+}
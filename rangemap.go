@@ -0,0 +1,155 @@
+package timerange
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// RangeMap - кусочно-постоянная функция времени: непересекающиеся интервалы
+// со значениями, упорядоченные по началу. Интервалы полуоткрытые [Start, End),
+// смежные интервалы с равными значениями объединяются автоматически.
+// Нулевое значение готово к использованию.
+type RangeMap[V comparable] struct {
+	entries []Interval[V]
+}
+
+func NewRangeMap[V comparable](intervals ...Interval[V]) *RangeMap[V] {
+	m := &RangeMap[V]{}
+	for _, iv := range intervals {
+		m.Set(iv.TimeRange, iv.Value)
+	}
+	return m
+}
+
+// Set записывает v на весь интервал tr, разрезая существующие записи так же, как Subtract.
+func (m *RangeMap[V]) Set(tr TimeRange, v V) {
+	if !tr.Start.Before(tr.End) {
+		return
+	}
+	m.replace(tr, []Interval[V]{{TimeRange: tr, Value: v}})
+}
+
+// Delete удаляет значения на интервале tr.
+func (m *RangeMap[V]) Delete(tr TimeRange) {
+	if !tr.Start.Before(tr.End) {
+		return
+	}
+	m.replace(tr, nil)
+}
+
+// Get возвращает значение в момент t.
+func (m *RangeMap[V]) Get(t time.Time) (V, bool) {
+	i := sort.Search(len(m.entries), func(i int) bool {
+		return m.entries[i].End.After(t)
+	})
+	if i < len(m.entries) && !t.Before(m.entries[i].Start) {
+		return m.entries[i].Value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Range возвращает записи, пересекающие q, обрезанные по его границам.
+func (m *RangeMap[V]) Range(q TimeRange) []Interval[V] {
+	lo, hi := m.overlapping(q)
+
+	var result []Interval[V]
+	for _, e := range m.entries[lo:hi] {
+		result = append(result, Interval[V]{
+			TimeRange: TimeRange{Start: maxTime(e.Start, q.Start), End: minTime(e.End, q.End)},
+			Value:     e.Value,
+		})
+	}
+	return result
+}
+
+// Intervals возвращает копию всех записей.
+func (m *RangeMap[V]) Intervals() []Interval[V] {
+	result := make([]Interval[V], len(m.entries))
+	copy(result, m.entries)
+	return result
+}
+
+func (m *RangeMap[V]) Len() int {
+	return len(m.entries)
+}
+
+// --- JSON Support ---
+
+func (m *RangeMap[V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.Intervals())
+}
+
+func (m *RangeMap[V]) UnmarshalJSON(data []byte) error {
+	var intervals []Interval[V]
+	if err := json.Unmarshal(data, &intervals); err != nil {
+		return err
+	}
+	*m = *NewRangeMap(intervals...)
+	return nil
+}
+
+// --- Helper Functions ---
+
+// overlapping возвращает границы [lo, hi) записей, пересекающих tr.
+func (m *RangeMap[V]) overlapping(tr TimeRange) (int, int) {
+	lo := sort.Search(len(m.entries), func(i int) bool {
+		return m.entries[i].End.After(tr.Start)
+	})
+	hi := sort.Search(len(m.entries), func(i int) bool {
+		return !m.entries[i].Start.Before(tr.End)
+	})
+	if hi < lo {
+		hi = lo
+	}
+	return lo, hi
+}
+
+// replace заменяет покрытие tr на inserted и объединяет новые стыки.
+func (m *RangeMap[V]) replace(tr TimeRange, inserted []Interval[V]) {
+	lo, hi := m.overlapping(tr)
+
+	var replacement []Interval[V]
+	if lo < hi && m.entries[lo].Start.Before(tr.Start) {
+		left := m.entries[lo]
+		left.End = tr.Start
+		replacement = append(replacement, left)
+	}
+	replacement = append(replacement, inserted...)
+	if lo < hi && m.entries[hi-1].End.After(tr.End) {
+		right := m.entries[hi-1]
+		right.Start = tr.End
+		replacement = append(replacement, right)
+	}
+
+	entries := make([]Interval[V], 0, len(m.entries)-(hi-lo)+len(replacement))
+	entries = append(entries, m.entries[:lo]...)
+	entries = append(entries, replacement...)
+	entries = append(entries, m.entries[hi:]...)
+	m.entries = entries
+
+	// Объединяем только затронутую область и ее соседей
+	from := lo - 1
+	if from < 0 {
+		from = 0
+	}
+	to := lo + len(replacement)
+	for i := to; i > from; i-- {
+		if i < len(m.entries) {
+			m.coalesceAt(i - 1)
+		}
+	}
+}
+
+// coalesceAt объединяет записи i и i+1, если они смежны и равны по значению.
+func (m *RangeMap[V]) coalesceAt(i int) {
+	if i+1 >= len(m.entries) {
+		return
+	}
+	curr, next := m.entries[i], m.entries[i+1]
+	if curr.End.Equal(next.Start) && curr.Value == next.Value {
+		m.entries[i].End = next.End
+		m.entries = append(m.entries[:i+1], m.entries[i+2:]...)
+	}
+}
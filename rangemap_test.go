@@ -0,0 +1,213 @@
+package timerange
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestRangeMapSet(t *testing.T) {
+	tests := []struct {
+		name     string
+		sets     []Interval[string]
+		expected []Interval[string]
+	}{
+		{
+			name: "disjoint spans are kept in order",
+			sets: []Interval[string]{
+				{TimeRange: hourRange(4, 6), Value: "b"},
+				{TimeRange: hourRange(0, 2), Value: "a"},
+			},
+			expected: []Interval[string]{
+				{TimeRange: hourRange(0, 2), Value: "a"},
+				{TimeRange: hourRange(4, 6), Value: "b"},
+			},
+		},
+		{
+			name: "inner span splits existing entry",
+			sets: []Interval[string]{
+				{TimeRange: hourRange(0, 6), Value: "a"},
+				{TimeRange: hourRange(2, 4), Value: "b"},
+			},
+			expected: []Interval[string]{
+				{TimeRange: hourRange(0, 2), Value: "a"},
+				{TimeRange: hourRange(2, 4), Value: "b"},
+				{TimeRange: hourRange(4, 6), Value: "a"},
+			},
+		},
+		{
+			name: "span overwrites several entries",
+			sets: []Interval[string]{
+				{TimeRange: hourRange(0, 2), Value: "a"},
+				{TimeRange: hourRange(2, 4), Value: "b"},
+				{TimeRange: hourRange(4, 6), Value: "c"},
+				{TimeRange: hourRange(1, 5), Value: "d"},
+			},
+			expected: []Interval[string]{
+				{TimeRange: hourRange(0, 1), Value: "a"},
+				{TimeRange: hourRange(1, 5), Value: "d"},
+				{TimeRange: hourRange(5, 6), Value: "c"},
+			},
+		},
+		{
+			name: "adjacent equal values are coalesced",
+			sets: []Interval[string]{
+				{TimeRange: hourRange(0, 2), Value: "a"},
+				{TimeRange: hourRange(4, 6), Value: "a"},
+				{TimeRange: hourRange(2, 4), Value: "a"},
+			},
+			expected: []Interval[string]{
+				{TimeRange: hourRange(0, 6), Value: "a"},
+			},
+		},
+		{
+			name: "restoring value merges split entry",
+			sets: []Interval[string]{
+				{TimeRange: hourRange(0, 6), Value: "a"},
+				{TimeRange: hourRange(2, 4), Value: "b"},
+				{TimeRange: hourRange(2, 4), Value: "a"},
+			},
+			expected: []Interval[string]{
+				{TimeRange: hourRange(0, 6), Value: "a"},
+			},
+		},
+		{
+			name: "empty span is ignored",
+			sets: []Interval[string]{
+				{TimeRange: hourRange(0, 2), Value: "a"},
+				{TimeRange: hourRange(1, 1), Value: "b"},
+			},
+			expected: []Interval[string]{
+				{TimeRange: hourRange(0, 2), Value: "a"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m RangeMap[string]
+			for _, iv := range tt.sets {
+				m.Set(iv.TimeRange, iv.Value)
+			}
+			if result := m.Intervals(); !equalIntervals(result, tt.expected) {
+				t.Errorf("Intervals() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRangeMapDelete(t *testing.T) {
+	m := NewRangeMap(
+		Interval[string]{TimeRange: hourRange(0, 4), Value: "a"},
+		Interval[string]{TimeRange: hourRange(4, 8), Value: "b"},
+	)
+	m.Delete(hourRange(2, 6))
+
+	expected := []Interval[string]{
+		{TimeRange: hourRange(0, 2), Value: "a"},
+		{TimeRange: hourRange(6, 8), Value: "b"},
+	}
+	if result := m.Intervals(); !equalIntervals(result, expected) {
+		t.Errorf("Intervals() = %v, want %v", result, expected)
+	}
+	if m.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", m.Len())
+	}
+}
+
+func TestRangeMapGet(t *testing.T) {
+	m := NewRangeMap(
+		Interval[int]{TimeRange: hourRange(0, 2), Value: 10},
+		Interval[int]{TimeRange: hourRange(2, 4), Value: 20},
+		Interval[int]{TimeRange: hourRange(6, 8), Value: 30},
+	)
+	base := hourRange(0, 0).Start
+
+	tests := []struct {
+		name   string
+		at     time.Time
+		want   int
+		wantOK bool
+	}{
+		{"start is included", base, 10, true},
+		{"boundary belongs to next entry", base.Add(2 * time.Hour), 20, true},
+		{"inside entry", base.Add(3 * time.Hour), 20, true},
+		{"end is excluded", base.Add(4 * time.Hour), 0, false},
+		{"gap", base.Add(5 * time.Hour), 0, false},
+		{"before first entry", base.Add(-time.Hour), 0, false},
+		{"after last entry", base.Add(8 * time.Hour), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := m.Get(tt.at)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Get() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRangeMapRange(t *testing.T) {
+	m := NewRangeMap(
+		Interval[int]{TimeRange: hourRange(0, 2), Value: 10},
+		Interval[int]{TimeRange: hourRange(2, 4), Value: 20},
+		Interval[int]{TimeRange: hourRange(6, 8), Value: 30},
+	)
+
+	tests := []struct {
+		name     string
+		query    TimeRange
+		expected []Interval[int]
+	}{
+		{
+			name:  "pieces are clipped to the window",
+			query: hourRange(1, 7),
+			expected: []Interval[int]{
+				{TimeRange: hourRange(1, 2), Value: 10},
+				{TimeRange: hourRange(2, 4), Value: 20},
+				{TimeRange: hourRange(6, 7), Value: 30},
+			},
+		},
+		{
+			name:     "window in gap",
+			query:    hourRange(4, 6),
+			expected: nil,
+		},
+		{
+			name:  "window inside single entry",
+			query: hourRange(2, 3),
+			expected: []Interval[int]{
+				{TimeRange: hourRange(2, 3), Value: 20},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := m.Range(tt.query); !equalIntervals(result, tt.expected) {
+				t.Errorf("Range() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRangeMapJSON(t *testing.T) {
+	m := NewRangeMap(
+		Interval[string]{TimeRange: hourRange(0, 2), Value: "alice"},
+		Interval[string]{TimeRange: hourRange(2, 4), Value: "bob"},
+	)
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var decoded RangeMap[string]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !equalIntervals(decoded.Intervals(), m.Intervals()) {
+		t.Errorf("Unmarshal() = %v, want %v", decoded.Intervals(), m.Intervals())
+	}
+}
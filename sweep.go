@@ -0,0 +1,102 @@
+package timerange
+
+import (
+	"sort"
+	"time"
+)
+
+// --- Concurrency Analytics ---
+
+// DepthProfile строит ступенчатую функцию глубины перекрытия: каждый интервал
+// результата несет число одновременно активных диапазонов. Диапазоны считаются
+// полуоткрытыми, поэтому смежные не перекрываются. Промежутки с нулевой
+// глубиной между первым началом и последним концом тоже входят в результат.
+func DepthProfile(ranges []TimeRange) []Interval[int] {
+	type event struct {
+		at    time.Time
+		delta int
+	}
+
+	events := make([]event, 0, 2*len(ranges))
+	for _, tr := range ranges {
+		if tr.Start.Before(tr.End) {
+			events = append(events, event{tr.Start, 1}, event{tr.End, -1})
+		}
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	// Концы обрабатываются раньше начал в ту же точку
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].at.Equal(events[j].at) {
+			return events[i].delta < events[j].delta
+		}
+		return events[i].at.Before(events[j].at)
+	})
+
+	var steps []Interval[int]
+	depth := 0
+	for i, e := range events {
+		depth += e.delta
+		if i+1 == len(events) || !events[i+1].at.After(e.at) {
+			continue
+		}
+		steps = append(steps, Interval[int]{
+			TimeRange: TimeRange{Start: e.at, End: events[i+1].at},
+			Value:     depth,
+		})
+	}
+	return Coalesce(steps)
+}
+
+// MaxConcurrency возвращает максимальную глубину перекрытия и интервалы, где она достигается.
+func MaxConcurrency(ranges []TimeRange) (int, []TimeRange) {
+	steps := DepthProfile(ranges)
+
+	maxDepth := 0
+	var peaks []TimeRange
+	for _, step := range steps {
+		switch {
+		case step.Value > maxDepth:
+			maxDepth = step.Value
+			peaks = []TimeRange{step.TimeRange}
+		case step.Value == maxDepth && maxDepth > 0:
+			peaks = append(peaks, step.TimeRange)
+		}
+	}
+	return maxDepth, peaks
+}
+
+// DepthAtLeast возвращает интервалы, где одновременно активно не меньше k диапазонов.
+func DepthAtLeast(ranges []TimeRange, k int) []TimeRange {
+	var result []TimeRange
+	for _, step := range DepthProfile(ranges) {
+		if step.Value < k {
+			continue
+		}
+		if n := len(result); n > 0 && result[n-1].End.Equal(step.Start) {
+			result[n-1].End = step.End
+		} else {
+			result = append(result, step.TimeRange)
+		}
+	}
+	return result
+}
+
+// AverageConcurrency возвращает среднюю по времени глубину перекрытия в пределах bounds.
+func AverageConcurrency(ranges []TimeRange, bounds TimeRange) (float64, error) {
+	if !bounds.Start.Before(bounds.End) {
+		return 0, ErrInvalidArgument
+	}
+
+	var weighted float64
+	for _, step := range DepthProfile(ranges) {
+		if !step.Overlaps(bounds) {
+			continue
+		}
+		overlap := minTime(step.End, bounds.End).Sub(maxTime(step.Start, bounds.Start))
+		weighted += float64(step.Value) * float64(overlap)
+	}
+	return weighted / float64(bounds.Duration()), nil
+}
@@ -0,0 +1,85 @@
+package timerange
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDepthProfile(t *testing.T) {
+	ranges := []TimeRange{
+		hourRange(0, 4),
+		hourRange(2, 6),
+		hourRange(6, 7),
+		hourRange(8, 9),
+	}
+
+	result := DepthProfile(ranges)
+	expected := []Interval[int]{
+		{TimeRange: hourRange(0, 2), Value: 1},
+		{TimeRange: hourRange(2, 4), Value: 2},
+		{TimeRange: hourRange(4, 7), Value: 1},
+		{TimeRange: hourRange(7, 8), Value: 0},
+		{TimeRange: hourRange(8, 9), Value: 1},
+	}
+	if !equalIntervals(result, expected) {
+		t.Errorf("DepthProfile() = %v, want %v", result, expected)
+	}
+
+	if result := DepthProfile(nil); result != nil {
+		t.Errorf("DepthProfile(nil) = %v, want nil", result)
+	}
+}
+
+func TestMaxConcurrency(t *testing.T) {
+	ranges := []TimeRange{
+		hourRange(0, 3),
+		hourRange(1, 2),
+		hourRange(2, 5),
+		hourRange(4, 6),
+		hourRange(4, 5),
+	}
+
+	depth, peaks := MaxConcurrency(ranges)
+	if depth != 3 {
+		t.Errorf("MaxConcurrency() depth = %d, want 3", depth)
+	}
+	if !compareRanges(peaks, []TimeRange{hourRange(4, 5)}) {
+		t.Errorf("MaxConcurrency() peaks = %v, want [%v]", peaks, hourRange(4, 5))
+	}
+}
+
+func TestDepthAtLeast(t *testing.T) {
+	ranges := []TimeRange{
+		hourRange(0, 3),
+		hourRange(1, 5),
+		hourRange(2, 4),
+		hourRange(6, 8),
+		hourRange(7, 9),
+	}
+
+	result := DepthAtLeast(ranges, 2)
+	expected := []TimeRange{hourRange(1, 4), hourRange(7, 8)}
+	if !compareRanges(result, expected) {
+		t.Errorf("DepthAtLeast() = %v, want %v", result, expected)
+	}
+}
+
+func TestAverageConcurrency(t *testing.T) {
+	ranges := []TimeRange{hourRange(0, 4), hourRange(2, 6)}
+
+	t.Run("valid bounds", func(t *testing.T) {
+		avg, err := AverageConcurrency(ranges, hourRange(0, 8))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(avg-1) > 1e-9 {
+			t.Errorf("AverageConcurrency() = %v, want 1", avg)
+		}
+	})
+
+	t.Run("empty bounds", func(t *testing.T) {
+		if _, err := AverageConcurrency(ranges, hourRange(1, 1)); err != ErrInvalidArgument {
+			t.Errorf("AverageConcurrency() error = %v, want ErrInvalidArgument", err)
+		}
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"time"
 )
@@ -26,7 +27,7 @@ func New(start, end time.Time) (TimeRange, error) {
 	if end.Before(start) {
 		return TimeRange{}, ErrInvalidRange
 	}
-	return TimeRange{Start: start, End: end}, nil
+	return normalized(TimeRange{Start: start, End: end}), nil
 }
 
 // --- Basic Operations ---
@@ -67,7 +68,7 @@ func Union(ranges []TimeRange) ([]TimeRange, error) {
 			merged = append(merged, curr)
 		}
 	}
-	return merged, nil
+	return normalizeAll(merged), nil
 }
 
 func Intersection(ranges []TimeRange) (TimeRange, error) {
@@ -90,44 +91,28 @@ func Intersection(ranges []TimeRange) (TimeRange, error) {
 	if maxStart.After(minEnd) {
 		return TimeRange{}, ErrNoIntersection
 	}
-	return TimeRange{Start: maxStart, End: minEnd}, nil
+	return normalized(TimeRange{Start: maxStart, End: minEnd}), nil
 }
 
 // --- Range Manipulation ---
 
 func (tr TimeRange) SplitByDuration(d time.Duration) []TimeRange {
-	if d <= 0 {
-		return []TimeRange{tr}
-	}
-
-	var ranges []TimeRange
-	current := tr.Start
-
-	for current.Before(tr.End) {
-		next := current.Add(d)
-		if next.After(tr.End) {
-			next = tr.End
-		}
-		ranges = append(ranges, TimeRange{Start: current, End: next})
-		current = next
-	}
-
-	return ranges
+	return normalizeAll(slices.Collect(tr.SplitByDurationSeq(d)))
 }
 
 func (tr TimeRange) Merge(other TimeRange) (TimeRange, error) {
 	if !tr.Overlaps(other) && !tr.IsAdjacent(other) {
 		return TimeRange{}, ErrNoOverlap
 	}
-	return TimeRange{
+	return normalized(TimeRange{
 		Start: minTime(tr.Start, other.Start),
 		End:   maxTime(tr.End, other.End),
-	}, nil
+	}), nil
 }
 
 func (tr TimeRange) Subtract(other TimeRange) []TimeRange {
 	if !tr.Overlaps(other) {
-		return []TimeRange{tr}
+		return []TimeRange{normalized(tr)}
 	}
 
 	var result []TimeRange
@@ -144,7 +129,30 @@ func (tr TimeRange) Subtract(other TimeRange) []TimeRange {
 			End:   tr.End,
 		})
 	}
-	return result
+	return normalizeAll(result)
+}
+
+func (tr TimeRange) SubtractAll(others []TimeRange) []TimeRange {
+	merged, _ := MergeOverlapping(others)
+
+	var result []TimeRange
+	cursor := tr.Start
+	for _, other := range merged {
+		if !other.End.After(cursor) {
+			continue
+		}
+		if !other.Start.Before(tr.End) {
+			break
+		}
+		if other.Start.After(cursor) {
+			result = append(result, TimeRange{Start: cursor, End: other.Start})
+		}
+		cursor = other.End
+	}
+	if cursor.Before(tr.End) {
+		result = append(result, TimeRange{Start: cursor, End: tr.End})
+	}
+	return normalizeAll(result)
 }
 
 func (tr TimeRange) Gap(other TimeRange) TimeRange {
@@ -152,9 +160,9 @@ func (tr TimeRange) Gap(other TimeRange) TimeRange {
 		return TimeRange{}
 	}
 	if tr.End.Before(other.Start) {
-		return TimeRange{Start: tr.End, End: other.Start}
+		return normalized(TimeRange{Start: tr.End, End: other.Start})
 	}
-	return TimeRange{Start: other.End, End: tr.Start}
+	return normalized(TimeRange{Start: other.End, End: tr.Start})
 }
 
 func MergeOverlapping(ranges []TimeRange) ([]TimeRange, error) {
@@ -183,9 +191,11 @@ func MergeOverlapping(ranges []TimeRange) ([]TimeRange, error) {
 		}
 	}
 
-	return merged, nil
+	return normalizeAll(merged), nil
 }
 
+// FindGaps возвращает свободные интервалы в пределах bounds. Занятые
+// интервалы за границами bounds не учитываются.
 func FindGaps(occupied []TimeRange, bounds TimeRange) ([]TimeRange, error) {
 	merged, err := MergeOverlapping(occupied)
 	if err != nil {
@@ -196,6 +206,9 @@ func FindGaps(occupied []TimeRange, bounds TimeRange) ([]TimeRange, error) {
 	previousEnd := bounds.Start
 
 	for _, tr := range merged {
+		if !tr.Start.Before(bounds.End) {
+			break
+		}
 		if tr.Start.After(previousEnd) {
 			gaps = append(gaps, TimeRange{
 				Start: previousEnd,
@@ -214,7 +227,28 @@ func FindGaps(occupied []TimeRange, bounds TimeRange) ([]TimeRange, error) {
 		})
 	}
 
-	return gaps, nil
+	return normalizeAll(gaps), nil
+}
+
+// FindSlots возвращает подряд идущие свободные слоты длительностью d в пределах bounds.
+func FindSlots(occupied []TimeRange, bounds TimeRange, d time.Duration) ([]TimeRange, error) {
+	if d <= 0 {
+		return nil, ErrInvalidArgument
+	}
+	bounds = normalized(bounds)
+	gaps, err := FindGaps(occupied, bounds)
+	if err != nil {
+		return nil, err
+	}
+
+	var slots []TimeRange
+	for _, gap := range gaps {
+		end := minTime(gap.End, bounds.End)
+		for start := maxTime(gap.Start, bounds.Start); !start.Add(d).After(end); start = start.Add(d) {
+			slots = append(slots, TimeRange{Start: start, End: start.Add(d)})
+		}
+	}
+	return normalizeAll(slots), nil
 }
 
 // --- Utility Functions ---
@@ -296,6 +330,7 @@ func (tr *TimeRange) UnmarshalJSON(data []byte) error {
 		return errors.New("empty time range")
 	}
 
+	*tr = normalized(*tr)
 	return nil
 }
 
@@ -424,6 +424,57 @@ func TestFindGaps(t *testing.T) {
 			t.Errorf("Expected %v, got %v", expected, gaps)
 		}
 	})
+
+	t.Run("occupied outside bounds", func(t *testing.T) {
+		occupied := []TimeRange{
+			{Start: parseTime("2022-12-30"), End: parseTime("2022-12-31")},
+			{Start: parseTime("2023-01-04"), End: parseTime("2023-01-05")},
+			{Start: parseTime("2023-01-12"), End: parseTime("2023-01-13")},
+		}
+		expected := []TimeRange{
+			{Start: parseTime("2023-01-01"), End: parseTime("2023-01-04")},
+			{Start: parseTime("2023-01-05"), End: parseTime("2023-01-10")},
+		}
+		gaps, err := FindGaps(occupied, bounds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !compareRanges(gaps, expected) {
+			t.Errorf("Expected %v, got %v", expected, gaps)
+		}
+	})
+}
+
+func TestFindSlots(t *testing.T) {
+	bounds := TimeRange{
+		Start: time.Date(2023, 1, 1, 9, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+	occupied := []TimeRange{
+		{Start: time.Date(2023, 1, 1, 9, 30, 0, 0, time.UTC), End: time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{Start: time.Date(2023, 1, 1, 11, 15, 0, 0, time.UTC), End: time.Date(2023, 1, 1, 13, 0, 0, 0, time.UTC)},
+	}
+
+	t.Run("slots fit into gaps", func(t *testing.T) {
+		slots, err := FindSlots(occupied, bounds, 30*time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []TimeRange{
+			{Start: time.Date(2023, 1, 1, 9, 0, 0, 0, time.UTC), End: time.Date(2023, 1, 1, 9, 30, 0, 0, time.UTC)},
+			{Start: time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC), End: time.Date(2023, 1, 1, 10, 30, 0, 0, time.UTC)},
+			{Start: time.Date(2023, 1, 1, 10, 30, 0, 0, time.UTC), End: time.Date(2023, 1, 1, 11, 0, 0, 0, time.UTC)},
+		}
+		if !compareRanges(slots, expected) {
+			t.Errorf("FindSlots() = %v, want %v", slots, expected)
+		}
+	})
+
+	t.Run("invalid duration", func(t *testing.T) {
+		if _, err := FindSlots(occupied, bounds, 0); err != ErrInvalidArgument {
+			t.Errorf("FindSlots() error = %v, want ErrInvalidArgument", err)
+		}
+	})
 }
 
 func TestIsAdjacent(t *testing.T) {
@@ -547,6 +598,29 @@ func TestSubtract(t *testing.T) {
 	}
 }
 
+func TestSubtractAll(t *testing.T) {
+	tr := TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC),
+	}
+	others := []TimeRange{
+		{Start: time.Date(2023, 1, 1, 6, 0, 0, 0, time.UTC), End: time.Date(2023, 1, 1, 7, 0, 0, 0, time.UTC)},
+		{Start: time.Date(2022, 12, 31, 0, 0, 0, 0, time.UTC), End: time.Date(2023, 1, 1, 2, 0, 0, 0, time.UTC)},
+		{Start: time.Date(2023, 1, 1, 3, 0, 0, 0, time.UTC), End: time.Date(2023, 1, 1, 4, 0, 0, 0, time.UTC)},
+		{Start: time.Date(2023, 1, 1, 3, 30, 0, 0, time.UTC), End: time.Date(2023, 1, 1, 5, 0, 0, 0, time.UTC)},
+	}
+
+	result := tr.SubtractAll(others)
+	expected := []TimeRange{
+		{Start: time.Date(2023, 1, 1, 2, 0, 0, 0, time.UTC), End: time.Date(2023, 1, 1, 3, 0, 0, 0, time.UTC)},
+		{Start: time.Date(2023, 1, 1, 5, 0, 0, 0, time.UTC), End: time.Date(2023, 1, 1, 6, 0, 0, 0, time.UTC)},
+		{Start: time.Date(2023, 1, 1, 7, 0, 0, 0, time.UTC), End: time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)},
+	}
+	if !compareRanges(result, expected) {
+		t.Errorf("SubtractAll() = %v, want %v", result, expected)
+	}
+}
+
 func TestIsZero(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -804,6 +878,15 @@ func parseTime(s string) time.Time {
 	return t
 }
 
+// mayAt и mayRange задают время в мае 2024 года; 2024-05-06 - понедельник.
+func mayAt(day, hour int) time.Time {
+	return time.Date(2024, 5, day, hour, 0, 0, 0, time.UTC)
+}
+
+func mayRange(day, fromHour, toHour int) TimeRange {
+	return TimeRange{Start: mayAt(day, fromHour), End: mayAt(day, toHour)}
+}
+
 func compareRanges(a, b []TimeRange) bool {
 	if len(a) != len(b) {
 		return false
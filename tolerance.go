@@ -0,0 +1,106 @@
+package timerange
+
+import (
+	"sort"
+	"time"
+)
+
+// MergeOptions настраивает MergeWithOptions для зашумленных данных.
+type MergeOptions struct {
+	// Tolerance - максимальный промежуток между интервалами, который еще
+	// поглощается при объединении. Ноль дает поведение MergeOverlapping.
+	Tolerance time.Duration
+	// MinDuration - объединенные интервалы короче этого значения отбрасываются.
+	MinDuration time.Duration
+}
+
+// MergedRange - результат объединения и индексы входных интервалов, которые он поглотил.
+type MergedRange struct {
+	Range   TimeRange `json:"range"`
+	Sources []int     `json:"sources"`
+}
+
+// MergeResult содержит оставленные и отброшенные по MinDuration интервалы.
+type MergeResult struct {
+	Ranges  []MergedRange `json:"ranges"`
+	Dropped []MergedRange `json:"dropped,omitempty"`
+}
+
+// TimeRanges возвращает оставленные интервалы без сведений об источниках.
+func (r MergeResult) TimeRanges() []TimeRange {
+	result := make([]TimeRange, len(r.Ranges))
+	for i, merged := range r.Ranges {
+		result[i] = merged.Range
+	}
+	return result
+}
+
+// MergeWithOptions объединяет интервалы, промежуток между которыми не превышает
+// opts.Tolerance, и отбрасывает результаты короче opts.MinDuration.
+func MergeWithOptions(ranges []TimeRange, opts MergeOptions) (MergeResult, error) {
+	if opts.Tolerance < 0 || opts.MinDuration < 0 {
+		return MergeResult{}, ErrInvalidArgument
+	}
+	if len(ranges) == 0 {
+		return MergeResult{}, nil
+	}
+
+	order := make([]int, len(ranges))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return ranges[order[i]].Start.Before(ranges[order[j]].Start)
+	})
+
+	first := ranges[order[0]]
+	merged := []MergedRange{{Range: first, Sources: []int{order[0]}}}
+	for _, idx := range order[1:] {
+		current := ranges[idx]
+		last := &merged[len(merged)-1]
+
+		if !current.Start.After(last.Range.End.Add(opts.Tolerance)) {
+			last.Range.End = maxTime(last.Range.End, current.End)
+			last.Sources = append(last.Sources, idx)
+		} else {
+			merged = append(merged, MergedRange{Range: current, Sources: []int{idx}})
+		}
+	}
+
+	var result MergeResult
+	for _, m := range merged {
+		sort.Ints(m.Sources)
+		m.Range = normalized(m.Range)
+		if m.Range.Duration() < opts.MinDuration {
+			result.Dropped = append(result.Dropped, m)
+		} else {
+			result.Ranges = append(result.Ranges, m)
+		}
+	}
+	return result, nil
+}
+
+// GapOptions настраивает FindGapsWithOptions.
+type GapOptions struct {
+	// MinGap - промежутки короче этого значения не считаются свободными.
+	MinGap time.Duration
+}
+
+// FindGapsWithOptions работает как FindGaps, но пропускает промежутки короче opts.MinGap.
+func FindGapsWithOptions(occupied []TimeRange, bounds TimeRange, opts GapOptions) ([]TimeRange, error) {
+	if opts.MinGap < 0 {
+		return nil, ErrInvalidArgument
+	}
+	gaps, err := FindGaps(occupied, bounds)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []TimeRange
+	for _, gap := range gaps {
+		if gap.Duration() >= opts.MinGap {
+			result = append(result, gap)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,132 @@
+package timerange
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func minuteRange(from, to int) TimeRange {
+	base := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	return TimeRange{
+		Start: base.Add(time.Duration(from) * time.Minute),
+		End:   base.Add(time.Duration(to) * time.Minute),
+	}
+}
+
+func TestMergeWithOptions(t *testing.T) {
+	// Фрагменты с разрывами в 1 и 2 минуты и один одиночный короткий всплеск
+	readings := []TimeRange{
+		minuteRange(12, 20),
+		minuteRange(0, 10),
+		minuteRange(11, 12),
+		minuteRange(40, 41),
+		minuteRange(60, 70),
+	}
+
+	tests := []struct {
+		name            string
+		opts            MergeOptions
+		expectedRanges  []TimeRange
+		expectedSources [][]int
+		expectedDropped []TimeRange
+	}{
+		{
+			name:            "zero tolerance merges only touching ranges",
+			opts:            MergeOptions{},
+			expectedRanges:  []TimeRange{minuteRange(0, 10), minuteRange(11, 20), minuteRange(40, 41), minuteRange(60, 70)},
+			expectedSources: [][]int{{1}, {0, 2}, {3}, {4}},
+		},
+		{
+			name:            "tolerance absorbs small gaps",
+			opts:            MergeOptions{Tolerance: time.Minute},
+			expectedRanges:  []TimeRange{minuteRange(0, 20), minuteRange(40, 41), minuteRange(60, 70)},
+			expectedSources: [][]int{{0, 1, 2}, {3}, {4}},
+		},
+		{
+			name:            "short results are dropped",
+			opts:            MergeOptions{Tolerance: time.Minute, MinDuration: 5 * time.Minute},
+			expectedRanges:  []TimeRange{minuteRange(0, 20), minuteRange(60, 70)},
+			expectedSources: [][]int{{0, 1, 2}, {4}},
+			expectedDropped: []TimeRange{minuteRange(40, 41)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := MergeWithOptions(readings, tt.opts)
+			if err != nil {
+				t.Fatalf("MergeWithOptions() error = %v", err)
+			}
+			if ranges := result.TimeRanges(); !compareRanges(ranges, tt.expectedRanges) {
+				t.Errorf("MergeWithOptions() ranges = %v, want %v", ranges, tt.expectedRanges)
+			}
+			var sources [][]int
+			for _, m := range result.Ranges {
+				sources = append(sources, m.Sources)
+			}
+			if !reflect.DeepEqual(sources, tt.expectedSources) {
+				t.Errorf("MergeWithOptions() sources = %v, want %v", sources, tt.expectedSources)
+			}
+			var dropped []TimeRange
+			for _, m := range result.Dropped {
+				dropped = append(dropped, m.Range)
+			}
+			if !compareRanges(dropped, tt.expectedDropped) {
+				t.Errorf("MergeWithOptions() dropped = %v, want %v", dropped, tt.expectedDropped)
+			}
+		})
+	}
+
+	t.Run("negative tolerance", func(t *testing.T) {
+		if _, err := MergeWithOptions(readings, MergeOptions{Tolerance: -time.Second}); err != ErrInvalidArgument {
+			t.Errorf("MergeWithOptions() error = %v, want ErrInvalidArgument", err)
+		}
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		result, err := MergeWithOptions(nil, MergeOptions{Tolerance: time.Minute})
+		if err != nil || len(result.Ranges) != 0 {
+			t.Errorf("MergeWithOptions() = %v, %v, want empty", result, err)
+		}
+	})
+}
+
+func TestFindGapsWithOptions(t *testing.T) {
+	occupied := []TimeRange{minuteRange(0, 10), minuteRange(11, 20), minuteRange(30, 40)}
+	bounds := minuteRange(0, 60)
+
+	tests := []struct {
+		name     string
+		opts     GapOptions
+		expected []TimeRange
+	}{
+		{
+			name:     "no minimum",
+			opts:     GapOptions{},
+			expected: []TimeRange{minuteRange(10, 11), minuteRange(20, 30), minuteRange(40, 60)},
+		},
+		{
+			name:     "short gaps are skipped",
+			opts:     GapOptions{MinGap: 5 * time.Minute},
+			expected: []TimeRange{minuteRange(20, 30), minuteRange(40, 60)},
+		},
+		{
+			name:     "minimum is inclusive",
+			opts:     GapOptions{MinGap: 10 * time.Minute},
+			expected: []TimeRange{minuteRange(20, 30), minuteRange(40, 60)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := FindGapsWithOptions(occupied, bounds, tt.opts)
+			if err != nil {
+				t.Fatalf("FindGapsWithOptions() error = %v", err)
+			}
+			if !compareRanges(result, tt.expected) {
+				t.Errorf("FindGapsWithOptions() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
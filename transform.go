@@ -0,0 +1,66 @@
+package timerange
+
+import "time"
+
+// --- Transformations ---
+
+// Все преобразования возвращают новый интервал через New, поэтому результат с
+// концом раньше начала дает ErrInvalidRange.
+
+// Shift сдвигает интервал на d, сохраняя длительность.
+func (tr TimeRange) Shift(d time.Duration) (TimeRange, error) {
+	return New(tr.Start.Add(d), tr.End.Add(d))
+}
+
+// Extend раздвигает интервал на before в прошлое и на after в будущее.
+// Отрицательные значения сужают интервал.
+func (tr TimeRange) Extend(before, after time.Duration) (TimeRange, error) {
+	return New(tr.Start.Add(-before), tr.End.Add(after))
+}
+
+// Pad раздвигает интервал на d с обеих сторон, например для буфера вокруг встречи.
+func (tr TimeRange) Pad(d time.Duration) (TimeRange, error) {
+	return tr.Extend(d, d)
+}
+
+// Shrink сужает интервал на d с обеих сторон.
+func (tr TimeRange) Shrink(d time.Duration) (TimeRange, error) {
+	return tr.Extend(-d, -d)
+}
+
+// ScaleAround масштабирует интервал относительно anchor: расстояния от anchor
+// до границ умножаются на factor. ScaleAround(tr.Start, 2) удваивает длительность.
+func (tr TimeRange) ScaleAround(anchor time.Time, factor float64) (TimeRange, error) {
+	if factor < 0 {
+		return TimeRange{}, ErrInvalidArgument
+	}
+	scale := func(t time.Time) time.Time {
+		return anchor.Add(time.Duration(float64(t.Sub(anchor)) * factor))
+	}
+	return New(scale(tr.Start), scale(tr.End))
+}
+
+// WithDuration возвращает интервал с тем же началом и длительностью d.
+func (tr TimeRange) WithDuration(d time.Duration) (TimeRange, error) {
+	return New(tr.Start, tr.Start.Add(d))
+}
+
+// ShiftDate сдвигает обе границы на календарные годы, месяцы и дни (как AddDate),
+// сохраняя время суток при переходах на летнее время.
+func (tr TimeRange) ShiftDate(years, months, days int) (TimeRange, error) {
+	return New(tr.Start.AddDate(years, months, days), tr.End.AddDate(years, months, days))
+}
+
+// TransformAll применяет fn к каждому интервалу и объединяет результаты,
+// которые стали пересекаться или смежными. Первая ошибка fn прерывает обработку.
+func TransformAll(ranges []TimeRange, fn func(TimeRange) (TimeRange, error)) ([]TimeRange, error) {
+	transformed := make([]TimeRange, 0, len(ranges))
+	for _, tr := range ranges {
+		result, err := fn(tr)
+		if err != nil {
+			return nil, err
+		}
+		transformed = append(transformed, result)
+	}
+	return MergeOverlapping(transformed)
+}
@@ -0,0 +1,143 @@
+package timerange
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTransformations(t *testing.T) {
+	meeting := hourRange(10, 12)
+	base := hourRange(0, 0).Start
+
+	tests := []struct {
+		name        string
+		transform   func(TimeRange) (TimeRange, error)
+		expected    TimeRange
+		expectedErr error
+	}{
+		{
+			name:      "shift forward",
+			transform: func(tr TimeRange) (TimeRange, error) { return tr.Shift(time.Hour) },
+			expected:  hourRange(11, 13),
+		},
+		{
+			name:      "shift backward",
+			transform: func(tr TimeRange) (TimeRange, error) { return tr.Shift(-2 * time.Hour) },
+			expected:  hourRange(8, 10),
+		},
+		{
+			name:      "extend",
+			transform: func(tr TimeRange) (TimeRange, error) { return tr.Extend(time.Hour, 2*time.Hour) },
+			expected:  hourRange(9, 14),
+		},
+		{
+			name:      "pad",
+			transform: func(tr TimeRange) (TimeRange, error) { return tr.Pad(time.Hour) },
+			expected:  hourRange(9, 13),
+		},
+		{
+			name:      "shrink to empty",
+			transform: func(tr TimeRange) (TimeRange, error) { return tr.Shrink(time.Hour) },
+			expected:  hourRange(11, 11),
+		},
+		{
+			name:        "shrink past middle",
+			transform:   func(tr TimeRange) (TimeRange, error) { return tr.Shrink(2 * time.Hour) },
+			expectedErr: ErrInvalidRange,
+		},
+		{
+			name:      "scale around start",
+			transform: func(tr TimeRange) (TimeRange, error) { return tr.ScaleAround(tr.Start, 2) },
+			expected:  hourRange(10, 14),
+		},
+		{
+			name: "scale around midpoint",
+			transform: func(tr TimeRange) (TimeRange, error) {
+				return tr.ScaleAround(base.Add(11*time.Hour), 0.5)
+			},
+			expected: TimeRange{Start: base.Add(10*time.Hour + 30*time.Minute), End: base.Add(11*time.Hour + 30*time.Minute)},
+		},
+		{
+			name:        "negative scale",
+			transform:   func(tr TimeRange) (TimeRange, error) { return tr.ScaleAround(tr.Start, -1) },
+			expectedErr: ErrInvalidArgument,
+		},
+		{
+			name:      "with duration",
+			transform: func(tr TimeRange) (TimeRange, error) { return tr.WithDuration(30 * time.Minute) },
+			expected:  TimeRange{Start: base.Add(10 * time.Hour), End: base.Add(10*time.Hour + 30*time.Minute)},
+		},
+		{
+			name:        "negative duration",
+			transform:   func(tr TimeRange) (TimeRange, error) { return tr.WithDuration(-time.Minute) },
+			expectedErr: ErrInvalidRange,
+		},
+		{
+			name:      "shift date",
+			transform: func(tr TimeRange) (TimeRange, error) { return tr.ShiftDate(0, 1, 1) },
+			expected:  TimeRange{Start: base.AddDate(0, 1, 1).Add(10 * time.Hour), End: base.AddDate(0, 1, 1).Add(12 * time.Hour)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.transform(meeting)
+			if err != tt.expectedErr {
+				t.Fatalf("error = %v, want %v", err, tt.expectedErr)
+			}
+			if err == nil && !result.Equal(tt.expected) {
+				t.Errorf("result = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestShiftDateKeepsWallClock(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+	// Переход на летнее время 31 марта 2024
+	tr := TimeRange{
+		Start: time.Date(2024, 3, 30, 9, 0, 0, 0, loc),
+		End:   time.Date(2024, 3, 30, 17, 0, 0, 0, loc),
+	}
+
+	result, err := tr.ShiftDate(0, 0, 1)
+	if err != nil {
+		t.Fatalf("ShiftDate() error = %v", err)
+	}
+	if result.Start.Hour() != 9 || result.End.Hour() != 17 {
+		t.Errorf("ShiftDate() = %v, want 09:00-17:00 local", result)
+	}
+}
+
+func TestTransformAll(t *testing.T) {
+	meetings := []TimeRange{hourRange(9, 10), hourRange(11, 12), hourRange(15, 16)}
+
+	t.Run("padding re-merges neighbours", func(t *testing.T) {
+		result, err := TransformAll(meetings, func(tr TimeRange) (TimeRange, error) {
+			return tr.Pad(30 * time.Minute)
+		})
+		if err != nil {
+			t.Fatalf("TransformAll() error = %v", err)
+		}
+		base := hourRange(0, 0).Start
+		expected := []TimeRange{
+			{Start: base.Add(8*time.Hour + 30*time.Minute), End: base.Add(12*time.Hour + 30*time.Minute)},
+			{Start: base.Add(14*time.Hour + 30*time.Minute), End: base.Add(16*time.Hour + 30*time.Minute)},
+		}
+		if !compareRanges(result, expected) {
+			t.Errorf("TransformAll() = %v, want %v", result, expected)
+		}
+	})
+
+	t.Run("error stops processing", func(t *testing.T) {
+		_, err := TransformAll(meetings, func(tr TimeRange) (TimeRange, error) {
+			return tr.Shrink(time.Hour)
+		})
+		if err != ErrInvalidRange {
+			t.Errorf("TransformAll() error = %v, want ErrInvalidRange", err)
+		}
+	})
+}